@@ -1,10 +1,10 @@
 module github.com/aarif123456/safegoroutines
 
-go 1.19
+go 1.25.0
 
-require golang.org/x/tools v0.13.0
+require golang.org/x/tools v0.47.0
 
 require (
-	golang.org/x/mod v0.12.0 // indirect
-	golang.org/x/sys v0.12.0 // indirect
+	golang.org/x/mod v0.37.0 // indirect
+	golang.org/x/sync v0.21.0 // indirect
 )
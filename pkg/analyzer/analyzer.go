@@ -4,15 +4,55 @@ import (
 	"flag"
 	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
 
 	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/analysis/passes/ctrlflow"
 	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
 	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
 )
 
 //nolint:gochecknoglobals
-var flagSet flag.FlagSet
+var (
+	flagSet flag.FlagSet
+
+	// recoverFirst requires the recover to be deferred before any other statement in a Goroutine.
+	recoverFirst bool
+	// checkDependencies reports calls to functions from other packages that start a Goroutine without
+	// a defer recover.
+	checkDependencies bool
+	// checkExports reports the functions exported to C that don't have a defer recover.
+	checkExports bool
+	// checkTerminating reports the Goroutines that can terminate the process, which skips their
+	// recover. terminatingFuncFlag adds to the functions that terminate the process.
+	checkTerminating    bool
+	terminatingFuncFlag = funcList{}
+
+	// safeLauncherFlag, unsafeLauncherFlag and forbiddenLauncherFlag configure the functions that run
+	// callbacks on their own Goroutines, see launcherKind. configPath is a file that configures them
+	// as well.
+	safeLauncherFlag      = funcList{}
+	unsafeLauncherFlag    = funcList{}
+	forbiddenLauncherFlag = funcList{}
+	configPath            string
+)
+
+//nolint:gochecknoinits
+func init() {
+	flagSet.BoolVar(&recoverFirst, "recover-first", false, "require the recover to be deferred by the first statement of a Goroutine, instead of allowing statements that can't panic before it")
+	flagSet.Var(safeLauncherFlag, "safe-launchers", "comma separated functions that always recover for the callbacks they run e.g. (*example.com/pool.Pool).Go")
+	flagSet.Var(unsafeLauncherFlag, "unsafe-launchers", "comma separated functions that run their callbacks on a new Goroutine, so the callbacks are checked like a go statement e.g. (*golang.org/x/sync/errgroup.Group).Go")
+	flagSet.Var(forbiddenLauncherFlag, "forbidden-launchers", "comma separated functions that must never be called")
+	flagSet.StringVar(&configPath, "config", "", "JSON file with the safeLaunchers, unsafeLaunchers, forbiddenLaunchers and terminatingFuncs")
+	flagSet.BoolVar(&checkDependencies, "check-dependencies", false, "report calls to functions from other packages that start a Goroutine without a defer recover")
+	flagSet.BoolVar(&checkExports, "check-exports", false, "report functions exported to C by an //export directive that don't have a defer recover")
+	flagSet.BoolVar(&checkTerminating, "check-terminating", false, "report Goroutines that can terminate the process e.g. by calling log.Fatal or os.Exit, since their recover never runs")
+	flagSet.Var(terminatingFuncFlag, "terminating-funcs", "comma separated functions that terminate the process, on top of the standard library ones e.g. example.com/internal/logger.Fatal")
+}
 
 func NewAnalyzer() *analysis.Analyzer {
 	return &analysis.Analyzer{
@@ -20,17 +60,86 @@ func NewAnalyzer() *analysis.Analyzer {
 		Doc:       "linter that ensures every Goroutine has a defer to catch it. This is required because recover handler are not inherited by child Goroutines in Go",
 		Run:       run,
 		Flags:     flagSet,
-		Requires:  []*analysis.Analyzer{inspect.Analyzer},
-		FactTypes: []analysis.Fact{new(isSafeFact)},
+		Requires:  []*analysis.Analyzer{inspect.Analyzer, buildssa.Analyzer, ctrlflow.Analyzer},
+		FactTypes: []analysis.Fact{new(isSafeFact), new(isRecoverHandlerFact), new(alwaysPanicsFact), new(onlySafeCallsFact), new(noPanicFact), new(typeParamObligationsFact), new(spawnsParamFact), new(launcherFact), new(spawnsUnsafeGoroutineFact), new(safeFieldFact), new(returnsSafeFuncFact), new(safeFuncVarFact), new(delegatesToReceiverFact), new(terminatesProcessFact)},
 	}
 }
 
 func run(pass *analysis.Pass) (any, error) {
+	l, err := getLaunchers()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := annotateNoPanic(pass); err != nil {
+		return nil, err
+	}
+
+	if err := annotateAlwaysPanics(pass); err != nil {
+		return nil, err
+	}
+
+	if err := annotateRecoverHandlers(pass); err != nil {
+		return nil, err
+	}
+
 	if err := annotateSafeFunc(pass); err != nil {
 		return nil, err
 	}
 
-	if err := validateGoroutines(pass); err != nil {
+	if err := annotateOnlySafeCalls(pass); err != nil {
+		return nil, err
+	}
+
+	if err := annotateDelegatingMethods(pass); err != nil {
+		return nil, err
+	}
+
+	if err := annotateSafeFactories(pass); err != nil {
+		return nil, err
+	}
+
+	if err := annotateSafeFields(pass); err != nil {
+		return nil, err
+	}
+
+	if err := annotateSafeFuncVars(pass); err != nil {
+		return nil, err
+	}
+
+	if err := annotateTypeParamObligations(pass); err != nil {
+		return nil, err
+	}
+
+	if err := annotateLaunchers(pass); err != nil {
+		return nil, err
+	}
+
+	if err := annotateSpawnedParams(pass); err != nil {
+		return nil, err
+	}
+
+	if err := annotateUnsafeSpawns(pass, l); err != nil {
+		return nil, err
+	}
+
+	if err := annotateTerminatingFuncs(pass); err != nil {
+		return nil, err
+	}
+
+	if err := validateGoroutines(pass, l); err != nil {
+		return nil, err
+	}
+
+	if err := validateExports(pass); err != nil {
+		return nil, err
+	}
+
+	if err := validateTerminatingGoroutines(pass); err != nil {
+		return nil, err
+	}
+
+	if err := validateDependencyCalls(pass, l); err != nil {
 		return nil, err
 	}
 
@@ -53,7 +162,8 @@ func annotateSafeFunc(pass *analysis.Pass) error {
 			return
 		}
 
-		if !doesFuncContainRecover(fdecl.Body) {
+		if !doesFuncContainRecover(pass, fdecl) || getUnprotectedNode(pass, fdecl) != nil {
+			// A panic before the recover is deferred still brings down the host.
 			return
 		}
 
@@ -71,48 +181,17 @@ func annotateSafeFunc(pass *analysis.Pass) error {
 	return nil
 }
 
-// doesFuncContainRecover checks if function has a recover.
-func doesFuncContainRecover(blckStmt *ast.BlockStmt) bool {
-	for _, stmt := range blckStmt.List {
-		switch stmt := stmt.(type) {
-		case *ast.DeferStmt:
-			hasRecover := false
-			// TODO: maybe refactor into a function
-			ast.Inspect(stmt.Call.Fun, func(fnLitNode ast.Node) bool {
-				if _, ok := fnLitNode.(*ast.GoStmt); ok {
-					// Each Goroutine has it's recover handler, so we don't want to inspect it.
-					return false
-				}
-
-				// TODO: should we force the recover the be at the top off the function?
-				ident, ok := fnLitNode.(*ast.Ident)
-				if !ok {
-					return true
-				}
-
-				// track recovery
-				hasRecover = hasRecover || ident.Name == "recover"
-				return false
-			})
-
-			if hasRecover {
-				return true
-			}
-		// TODO: maybe check if it's just a bunch of function calls, and each function has a recover than the function is safe
-		default:
-			fmt.Printf("[doesFuncContainRecover] Not handling type: %T\n", stmt)
-		}
-	}
-
-	return false
-}
-
-func validateGoroutines(pass *analysis.Pass) error {
+func validateGoroutines(pass *analysis.Pass, l launchers) error {
 	inspector, ok := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 	if !ok {
 		return fmt.Errorf("Expected inspect.Analyzer to be an *inspector.Inspector, but got %T", pass.ResultOf[inspect.Analyzer])
 	}
 
+	df, err := newDataflow(pass)
+	if err != nil {
+		return err
+	}
+
 	nodeFilter := []ast.Node{
 		// TODO: instead go through function declaration, that way you can keep a Set of relevant assignments to get the relevant function literal assigned to the variable/selector being called.
 		// Track assignment to func, slice of function, nested map, structs containing functions where last value is functions. For fields in struct, do I want to assume the variable doesn't get reassigned? Or, do I need to follow the function call to make sure the field doesn't get reassigned? What if some complicated logic checks for assignment e.g. if rand() < 100, make function nil?
@@ -126,21 +205,208 @@ func validateGoroutines(pass *analysis.Pass) error {
 		(*ast.GoStmt)(nil), /* Find Goroutines */
 	}
 
+	funcDecls := getFuncDecls(pass)
+	obligated := getObligatedGoStmts(pass, funcDecls)
 	inspector.Preorder(nodeFilter, func(node ast.Node) {
 		goStmt := node.(*ast.GoStmt)
+		if obligated[goStmt] {
+			// The Goroutine depends on the arguments, so it's checked where the function is called.
+			return
+		}
+
+		// The syntax is enough for most Goroutines, otherwise we follow the assignments reaching it.
+		body := getGoroutineBody(pass, goStmt, funcDecls)
+		if unprotected := getUnprotectedNode(pass, body); unprotected != nil {
+			pass.Reportf(node.Pos(), "Goroutine can panic before its recover is deferred: `%s`", formatNode(pass, unprotected))
+			return
+		}
+
+		if isFuncSafe(pass, goStmt.Call.Fun) || df.isGoStmtSafe(goStmt) {
+			return
+		}
+
+		if reason := getUnsafeInterfaceReason(pass, df, goStmt); reason != "" {
+			pass.Reportf(node.Pos(), "Goroutine should have a defer recover: %s", reason)
+			return
+		}
+
+		if body != nil {
+			if reason := getIneffectiveRecoverReason(pass, body); reason != "" {
+				pass.Reportf(node.Pos(), "Goroutine should have a defer recover: %s", reason)
+				return
+			}
+		}
+
+		pass.Reportf(node.Pos(), "Goroutine should have a defer recover")
+	})
+
+	if err := validateInstantiations(pass, df, funcDecls); err != nil {
+		return err
+	}
+
+	if err := validateLaunchers(pass, df, l, funcDecls); err != nil {
+		return err
+	}
+
+	return validateSpawnedArgs(pass, df, l, funcDecls)
+}
+
+// annotateOnlySafeCalls marks the functions that only call safe functions. A panic in any of those
+// calls is recovered by the callee, so the function is safe to run in a Goroutine as well.
+func annotateOnlySafeCalls(pass *analysis.Pass) error {
+	markUntilFixpoint(getFuncDecls(pass), func(fn types.Object, fdecl *ast.FuncDecl) bool {
+		if pass.ImportObjectFact(fn, &isSafeFact{}) || !doesFuncOnlyCallSafeFuncs(pass, fdecl.Body) {
+			return false
+		}
+
+		pass.ExportObjectFact(fn, new(onlySafeCallsFact))
+		return true
+	})
+
+	return nil
+}
+
+// doesFuncOnlyCallSafeFuncs checks if every statement in the body is a call to a safe function, with
+// arguments that can't panic while they're evaluated.
+func doesFuncOnlyCallSafeFuncs(pass *analysis.Pass, body *ast.BlockStmt) bool {
+	if len(body.List) == 0 {
+		return false
+	}
+
+	for _, stmt := range body.List {
+		exprStmt, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			return false
+		}
 
-		if !isFuncSafe(pass, goStmt.Call.Fun) {
-			pass.Reportf(node.Pos(), "Goroutine should have a defer recover")
+		call, ok := exprStmt.X.(*ast.CallExpr)
+		if !ok || mayCallOperandsPanic(pass, call) {
+			return false
+		}
+
+		callee := typeutil.StaticCallee(pass.TypesInfo, call)
+		if callee == nil || !isObjectSafe(pass, callee) {
+			return false
 		}
+	}
+
+	return true
+}
+
+// isFuncLitSafe checks if the function literal recovers, or can't panic in the first place.
+func isFuncLitSafe(pass *analysis.Pass, fnLit *ast.FuncLit) bool {
+	return doesFuncContainRecover(pass, fnLit) || !mayPanic(pass, fnLit.Body)
+}
+
+// isObjectSafe checks if the function can run in a Goroutine without bringing down the host.
+func isObjectSafe(pass *analysis.Pass, tFn types.Object) bool {
+	tFn, _ = getFunctionOrigin(tFn)
+
+	return pass.ImportObjectFact(tFn, &isSafeFact{}) || pass.ImportObjectFact(tFn, &onlySafeCallsFact{}) ||
+		pass.ImportObjectFact(tFn, &noPanicFact{})
+}
+
+// getUnprotectedNode finds the first statement of the function that runs before its recover is
+// deferred, and could panic. With recoverFirst, every statement before the recover is reported.
+func getUnprotectedNode(pass *analysis.Pass, fn ast.Node) ast.Node {
+	if fn == nil {
+		return nil
+	}
+
+	paths := newRecoverPaths(pass, fn)
+	if paths == nil || paths.getCoverage() != recoverOnEveryPath {
+		// The function doesn't protect itself e.g. it only calls safe functions.
+		return nil
+	}
+
+	return paths.getFirstUnprotectedNode(func(node ast.Node) bool {
+		return recoverFirst || mayPanic(pass, node)
 	})
+}
+
+// getGoroutineBody gets the function literal or the declaration of the function in this package
+// that the Goroutine runs, if it can be found.
+func getGoroutineBody(pass *analysis.Pass, goStmt *ast.GoStmt, funcDecls map[types.Object]*ast.FuncDecl) ast.Node {
+	if fnLit, ok := goStmt.Call.Fun.(*ast.FuncLit); ok {
+		return fnLit
+	}
+
+	callee := typeutil.StaticCallee(pass.TypesInfo, goStmt.Call)
+	if callee == nil {
+		return nil
+	}
+
+	tFn, _ := getFunctionOrigin(callee)
+	if fdecl, ok := funcDecls[tFn]; ok {
+		return fdecl
+	}
 
 	return nil
 }
 
+// markUntilFixpoint calls mark with each entry, and removes the entries it marks, until it marks none
+// of the remaining ones. The entries depend on each other in any order e.g. functions calling each
+// other, so an entry that can't be marked yet may be once another one is.
+func markUntilFixpoint[K comparable, V any](entries map[K]V, mark func(K, V) bool) {
+	for changed := true; changed; {
+		changed = false
+		for k, v := range entries {
+			if mark(k, v) {
+				delete(entries, k)
+				changed = true
+			}
+		}
+	}
+}
+
+// collectUntilFixpoint collects the items of each function of this package e.g. the parameters it
+// starts a Goroutine with, until no function finds a new one. get is given the items found so far,
+// since a function gets the items of the functions it calls, which may not be found yet.
+func collectUntilFixpoint[T any](pass *analysis.Pass, get func(fdecl *ast.FuncDecl, known map[types.Object][]T) []T) map[types.Object][]T {
+	funcDecls := getFuncDecls(pass)
+	known := map[types.Object][]T{}
+	for changed := true; changed; {
+		changed = false
+		for fn, fdecl := range funcDecls {
+			if found := get(fdecl, known); len(found) > len(known[fn]) {
+				known[fn] = found
+				changed = true
+			}
+		}
+	}
+
+	return known
+}
+
+// getFuncDecls maps the functions declared in this package to their declaration.
+func getFuncDecls(pass *analysis.Pass) map[types.Object]*ast.FuncDecl {
+	funcDecls := map[types.Object]*ast.FuncDecl{}
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			fdecl, ok := decl.(*ast.FuncDecl)
+			if !ok || fdecl.Body == nil {
+				continue
+			}
+
+			if tFn := pass.TypesInfo.Defs[fdecl.Name]; tFn != nil {
+				funcDecls[tFn] = fdecl
+			}
+		}
+	}
+
+	return funcDecls
+}
+
 func isFuncSafe(pass *analysis.Pass, node ast.Node) bool {
+	return isFuncSafeVisiting(pass, node, nil)
+}
+
+// isFuncSafeVisiting checks if the function is safe, where visiting holds the collections we are
+// already checking, so collections that store each other's elements terminate.
+func isFuncSafeVisiting(pass *analysis.Pass, node ast.Node, visiting map[*types.Var]bool) bool {
 	switch fn := node.(type) {
 	case *ast.FuncLit:
-		return doesFuncContainRecover(fn.Body)
+		return isFuncLitSafe(pass, fn)
 	case *ast.Ident:
 		tFn := pass.TypesInfo.ObjectOf(fn)
 		if tFn == nil {
@@ -150,6 +416,17 @@ func isFuncSafe(pass *analysis.Pass, node ast.Node) bool {
 
 		if tFnFrom, ok := tFn.(*types.Var); ok {
 			tFn = tFnFrom.Origin()
+			if isMethodValueVarSafe(pass, tFnFrom) {
+				return true
+			}
+
+			if collection, ok := getRangeCollection(pass, tFnFrom); ok {
+				return isCollectionSafe(pass, collection, visiting)
+			}
+
+			if isPackageVar(tFnFrom) {
+				return isPackageVarSafe(pass, tFnFrom)
+			}
 		}
 
 		tFn, ok := getFunctionOrigin(tFn)
@@ -157,148 +434,90 @@ func isFuncSafe(pass *analysis.Pass, node ast.Node) bool {
 			fmt.Printf("Ident did not map to function: %q, %T\n", fn.Name, tFn)
 		}
 
-		return pass.ImportObjectFact(tFn, &isSafeFact{})
+		return isObjectSafe(pass, tFn)
 	case *ast.IndexExpr, *ast.IndexListExpr:
+		if collection, ok := getReadCollection(pass, fn.(ast.Expr)); ok {
+			return isCollectionSafe(pass, collection, visiting)
+		}
+
 		x := getIDFromIndexParam(fn)
 		id, _ := x.(*ast.Ident)
 		if id == nil {
 			return false
 		}
 
-		return isFuncSafe(pass, id)
+		return isFuncSafeVisiting(pass, id, visiting)
 	case *ast.SelectorExpr:
+		if iface, method, ok := getInterfaceMethod(pass, fn); ok {
+			return isInterfaceMethodSafe(pass, fn, iface, method)
+		}
+
+		if isDelegatingMethod(pass, fn) {
+			return isFuncSafeVisiting(pass, fn.X, visiting)
+		}
+
+		if safe, ok := isEmbeddedInterfaceMethodSafe(pass, fn); ok {
+			return safe
+		}
+
+		if value, ok := getSelectedValue(pass, fn); ok {
+			return isFuncSafeVisiting(pass, value, visiting)
+		}
+
+		if field, ok := getSelectedField(pass, fn); ok && isStructFieldSafe(pass, field) {
+			return true
+		}
+
 		id := fn.Sel
 
 		switch clit := fn.X.(type) {
 		case *ast.CompositeLit:
-			if len(clit.Elts) == 0 {
-				return isFuncSafe(pass, id) // If we have an empty composite literal, then the selector has to be a method.
-			}
-
-			// We want to treat anonymous types the same as name type, so we get the underlying type
-			clType, ok := getUnderlyingCompositeType(pass, clit)
-			if !ok {
-				return isFuncSafe(pass, id)
-			}
-			switch st := clType.(type) {
-			case *types.Struct:
-				switch structDecl := clit.Elts[0].(type) {
-				case *ast.KeyValueExpr:
-					// KeyValueExpr are structs declared in the following way
-					// myStruct{ myKey: myValue, myOtherKey, myOtherValue}
-					for i, elt := range clit.Elts {
-						elt, ok := elt.(*ast.KeyValueExpr)
-						if !ok {
-							panic(fmt.Sprintf("elt type at index %d, did not match value at index 0. initialType: %T, curType: %T", i, structDecl, elt))
-						}
-
-						k := elt.Key
-						kID, ok := k.(*ast.Ident)
-						if !ok {
-							fmt.Printf("Key type of Key-value expression in a struct declaration was not a Identifier%T\n", k)
-							continue
-						}
-
-						if kID.Name == id.Name {
-							return isFuncSafe(pass, elt.Value)
-						}
-					}
-				default:
-					// The other type of struct declaration e.g.
-					// myStruct{ myValue, myOtherValue}
-					i, matched := getMatchedFieldIndex(st, id)
-					if !matched {
-						fmt.Printf("Unmatched selector type %T", structDecl)
-						return isFuncSafe(pass, id)
-					}
-
-					return isFuncSafe(pass, clit.Elts[i])
-				}
-
-				return isFuncSafe(pass, id)
-			// TODO: handle slices, array and maps
-			default:
-				fmt.Printf("Unhandled composite literal declaration %T\n", clType)
-			}
+			// The fields set by the literal, including through embedded literals, are checked by
+			// getSelectedValue, so only methods and the fields it leaves out are left. The literals of
+			// slices, arrays and maps can only have methods selected.
 		case *ast.CallExpr:
 			tClitFn, ok := pass.TypesInfo.TypeOf(clit.Fun).(*types.Named)
 			if !ok {
 				// TODO: try to get this line to print
 				fmt.Printf("Call expression in composite literal has unexpected type: %q, want:*types.Named, got: %T\n", clit.Fun, pass.TypesInfo.TypeOf(clit.Fun))
-				return isFuncSafe(pass, id)
+				return isFuncSafeVisiting(pass, id, visiting)
 			}
 
-			switch tClitFn.Underlying().(type) {
-			case *types.Interface:
-				fmt.Printf("TODO: interface checking selector: %s, %+v, %T\n", id, clit.Args[0], clit.Args[0])
-				// TODO: We need to get the underlying type of the interface by analyzing `clit.Args`
-				// At the start, we can do a quick check to get the type only if a struct is passed in
-				// myVar:= myStruct{}; (f(myVar))
-				// i.e. . no chained function calls. Later, can try to handle interface being passed in as well
-				return isFuncSafe(pass, id)
-			default:
-				fmt.Printf("Unhandled call expression before selector: %q, %T\n", tClitFn.Underlying(), tClitFn.Underlying())
-			}
-		case *ast.Ident:
-			return isFuncSafe(pass, id)
+			fmt.Printf("Unhandled call expression before selector: %q, %T\n", tClitFn.Underlying(), tClitFn.Underlying())
+		case *ast.Ident, *ast.ParenExpr, *ast.StarExpr, *ast.TypeAssertExpr, *ast.SelectorExpr:
+			// The method or field is decided by the type of the operand e.g. `v.(myStruct).safe`.
+			return isFuncSafeVisiting(pass, id, visiting)
 		default:
 			fmt.Printf("Unknown CompositeLit type: %T\n", clit)
 		}
 
-		return isFuncSafe(pass, id)
+		return isFuncSafeVisiting(pass, id, visiting)
+	case *ast.CallExpr:
+		if operand, ok := getConversionOperand(pass, fn); ok {
+			return isFuncSafeVisiting(pass, operand, visiting)
+		}
+
+		return isFactoryCallSafe(pass, fn)
+	case *ast.ParenExpr:
+		return isFuncSafeVisiting(pass, fn.X, visiting)
+	case *ast.StarExpr:
+		// We can only tell which function the pointer points to, when its address was just taken e.g. `*&f`.
+		addr, ok := astutil.Unparen(fn.X).(*ast.UnaryExpr)
+		return ok && addr.Op == token.AND && isFuncSafeVisiting(pass, addr.X, visiting)
+	case *ast.TypeAssertExpr:
+		// The function asserted from an interface is only known when it was converted to the interface
+		// right before e.g. `any(f).(func())`.
+		operand, ok := getConversionOperand(pass, fn.X)
+		return ok && isFuncSafeVisiting(pass, operand, visiting)
+	case *ast.UnaryExpr:
+		collection, ok := getReadCollection(pass, fn)
+		return ok && isCollectionSafe(pass, collection, visiting)
 	default:
 		fmt.Printf("Unknown type: %T\n", fn)
 		return false
 	}
 }
 
-// getUnderlyingCompositeType gets the underlying type associated with the [composite declaration].
-// For example, for a struct e.g struct{}{}, myStructType{}, &myStruct{}, it'll return *types.Struct.
-// The returned type must be either struct, array, slice, or map type
-//
-// [composite declaration]: https://go.dev/ref/spec#Composite_literals
-func getUnderlyingCompositeType(pass *analysis.Pass, clit *ast.CompositeLit) (types.Type, bool) {
-	node := clit.Type
-
-	out := pass.TypesInfo.TypeOf(node)
-	if out == nil {
-		fmt.Printf("Could not find type of composite literal: %q", node)
-		return nil, false
-	}
-
-	for {
-		switch cur := out.(type) {
-		default:
-			fmt.Printf("getUnderlyingCompositeType returns invalid value %T", out)
-			return out, false
-		case *types.Struct, *types.Array, *types.Slice, *types.Map:
-			return out, true
-		case *types.Named:
-			for cur.Origin() != nil && cur.Origin() != cur {
-				// TODO: do I need this to get to the generic function original definition?
-				// Also, what other cases am I handling by accident?
-				cur = cur.Origin()
-			}
-
-			out = cur.Underlying()
-			for out.Underlying() != nil && out.Underlying() != out {
-				out = out.Underlying()
-			}
-		}
-	}
-}
-
-func getMatchedFieldIndex(st *types.Struct, id *ast.Ident) (int, bool) {
-	for i := 0; i < st.NumFields(); i++ {
-		fd := st.Field(i)
-		if id.Name == fd.Name() {
-			return i, true
-		}
-	}
-
-	return -1, false
-}
-
 func getIDFromIndexParam(n ast.Node) ast.Expr {
 	switch e := n.(type) {
 	case *ast.IndexExpr:
@@ -323,24 +542,3 @@ func getFunctionOrigin(tFn types.Object) (types.Object, bool) {
 
 	return cur, true
 }
-
-type isSafeFact struct{} // =>  *types.Func f is a function that won't panic
-
-func (*isSafeFact) AFact() {}
-
-func (*isSafeFact) String() string {
-	return "isSafe"
-}
-
-func (s *isSafeFact) GobDecode(data []byte) error {
-	if string(data) != "isSafe" {
-		return fmt.Errorf("invalid GOB data: %q", data)
-	}
-
-	s = &isSafeFact{}
-	return nil
-}
-
-func (*isSafeFact) GobEncode() ([]byte, error) {
-	return []byte("isSafe"), nil
-}
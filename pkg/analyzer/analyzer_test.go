@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"flag"
 	"os"
 	"path/filepath"
 	"testing"
@@ -9,11 +10,66 @@ import (
 )
 
 func TestLinter(t *testing.T) {
+	analysistest.Run(t, getTestdata(t), NewAnalyzer(), "pkg")
+}
+
+// TestFlags checks the packages that are only analyzed with some flags set.
+func TestFlags(t *testing.T) {
+	tests := []struct {
+		pkg   string
+		flags map[string]string
+	}{
+		{"recoverfirst", map[string]string{"recover-first": "true"}},
+		{"dependencies", map[string]string{"check-dependencies": "true"}},
+		{"exports", map[string]string{"check-exports": "true"}},
+		{"terminating", map[string]string{
+			"check-terminating": "true",
+			"terminating-funcs": "shutdown.Crash",
+		}},
+		{"launchers", map[string]string{
+			"config":              filepath.Join(getTestdata(t), "src", "launchers", "config.json"),
+			"safe-launchers":      "time.AfterFunc",
+			"forbidden-launchers": "pool.Spawn",
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pkg, func(t *testing.T) {
+			analyzer := NewAnalyzer()
+			t.Cleanup(func() { resetFlags(t) })
+
+			for name, value := range test.flags {
+				if err := analyzer.Flags.Set(name, value); err != nil {
+					t.Fatalf("Failed to set %s: %s", name, err)
+				}
+			}
+
+			analysistest.Run(t, getTestdata(t), analyzer, test.pkg)
+		})
+	}
+}
+
+// resetFlags restores every flag to its default. The flags are globals, so they're shared by every
+// test.
+func resetFlags(t *testing.T) {
+	t.Helper()
+
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if names, ok := f.Value.(funcList); ok {
+			clear(names)
+		} else if err := f.Value.Set(f.DefValue); err != nil {
+			t.Fatalf("Failed to reset %s: %s", f.Name, err)
+		}
+	})
+}
+
+func getTestdata(t *testing.T) string {
+	t.Helper()
+
 	wd, err := os.Getwd()
 	if err != nil {
 		t.Fatalf("Failed to get wd: %s", err)
 	}
 
-	testdata := filepath.Join(filepath.Dir(filepath.Dir(wd)), "testdata")
-	analysistest.Run(t, testdata, NewAnalyzer(), "pkg")
+	return filepath.Join(filepath.Dir(filepath.Dir(wd)), "testdata")
 }
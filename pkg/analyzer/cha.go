@@ -0,0 +1,500 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ssa"
+)
+
+// getInterfaceMethod gets the interface and the method called through it, when the selector calls
+// a method of an interface e.g. `v.safe()` where v is a someInterface.
+func getInterfaceMethod(pass *analysis.Pass, sel *ast.SelectorExpr) (*types.Interface, *types.Func, bool) {
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
+		return nil, nil, false
+	}
+
+	iface, ok := selection.Recv().Underlying().(*types.Interface)
+	if !ok {
+		return nil, nil, false
+	}
+
+	method, ok := selection.Obj().(*types.Func)
+	return iface, method, ok
+}
+
+// isInterfaceMethodSafe checks if the method called through an interface is safe for every type
+// it may be called on. When the interface is converted from a concrete type right before the call,
+// we only need to check that type, otherwise we check every type in the program that implements
+// the interface, as long as no other package can implement it.
+func isInterfaceMethodSafe(pass *analysis.Pass, sel *ast.SelectorExpr, iface *types.Interface, method *types.Func) bool {
+	if ty, ok := getConvertedType(pass, sel.X); ok {
+		return isMethodSafe(pass, ty, method)
+	}
+
+	if !isInterfaceClosed(pass, pass.TypesInfo.TypeOf(sel.X), method.Name()) {
+		return false
+	}
+
+	impls := getImplementations(pass, iface)
+
+	return len(impls) > 0 && len(getUnsafeImplementations(pass, impls, method)) == 0
+}
+
+// getUnsafeInterfaceReason lists the types whose implementation of the method called through an
+// interface is unsafe. We only list the types the interface can hold when the dataflow can trace
+// them, otherwise every type that implements the interface.
+func getUnsafeInterfaceReason(pass *analysis.Pass, df *dataflow, goStmt *ast.GoStmt) string {
+	sel, ok := astutil.Unparen(goStmt.Call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+
+	iface, method, ok := getInterfaceMethod(pass, sel)
+	if !ok {
+		return ""
+	}
+
+	impls, traced := df.getInvokedTypes(goStmt)
+	if !traced {
+		impls = getImplementations(pass, iface)
+	}
+
+	if unsafe := getUnsafeImplementations(pass, impls, method); len(unsafe) > 0 {
+		return fmt.Sprintf("`%s` has no recover in %s", method.Name(), formatTypes(pass, unsafe))
+	}
+
+	if ty := pass.TypesInfo.TypeOf(sel.X); !traced && !isInterfaceClosed(pass, ty, method.Name()) {
+		return getOpenInterfaceReason(pass, ty)
+	}
+
+	return ""
+}
+
+// getOpenInterfaceReason explains that the interface can hold types we can't check.
+func getOpenInterfaceReason(pass *analysis.Pass, ty types.Type) string {
+	return fmt.Sprintf("`%s` can be implemented by packages that aren't analyzed yet", formatTypes(pass, []types.Type{ty}))
+}
+
+// getConversionOperand gets the value converted by the expression e.g. `handle` in
+// `handlerFunc(handle)`.
+func getConversionOperand(pass *analysis.Pass, x ast.Expr) (ast.Expr, bool) {
+	call, ok := astutil.Unparen(x).(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, false
+	}
+
+	if tv, ok := pass.TypesInfo.Types[call.Fun]; !ok || !tv.IsType() {
+		return nil, false
+	}
+
+	return call.Args[0], true
+}
+
+// getConvertedType gets the concrete type of the value converted to an interface e.g. myStruct in
+// `someInterface(myStruct{})`.
+func getConvertedType(pass *analysis.Pass, x ast.Expr) (types.Type, bool) {
+	operand, ok := getConversionOperand(pass, x)
+	if !ok {
+		return nil, false
+	}
+
+	ty := pass.TypesInfo.TypeOf(operand)
+	if ty == nil || types.IsInterface(ty) {
+		return nil, false
+	}
+
+	return ty, true
+}
+
+// getImplementations finds the types declared in this package, or the packages it imports, that
+// implement the interface. These are the only types the interface can hold as far as we can see, since
+// the packages that import us aren't analyzed yet. The types declared inside the functions of this
+// package, and the struct types that get their methods from an embedded field, are included as well.
+func getImplementations(pass *analysis.Pass, iface *types.Interface) []types.Type {
+	var impls []types.Type
+	add := func(ty types.Type) {
+		if types.IsInterface(ty) {
+			return
+		}
+
+		impl, ok := getImplementingType(ty, iface)
+		if !ok {
+			return
+		}
+
+		for _, cur := range impls {
+			if types.Identical(cur, impl) {
+				return
+			}
+		}
+
+		impls = append(impls, impl)
+	}
+
+	visited := map[*types.Package]bool{}
+
+	var visit func(pkg *types.Package)
+	visit = func(pkg *types.Package) {
+		if visited[pkg] {
+			return
+		}
+
+		visited[pkg] = true
+		for _, imported := range pkg.Imports() {
+			visit(imported)
+		}
+
+		scope := pkg.Scope()
+		for _, name := range scope.Names() {
+			typeName, ok := scope.Lookup(name).(*types.TypeName)
+			if ok && !typeName.IsAlias() {
+				add(typeName.Type())
+			}
+		}
+	}
+
+	visit(pass.Pkg)
+
+	for _, ty := range getLocalTypes(pass) {
+		add(ty)
+	}
+
+	return impls
+}
+
+// getLocalTypes gets the types declared inside the functions of this package, and the struct types
+// used without a name e.g. `struct{ myStruct }`, sorted by name.
+func getLocalTypes(pass *analysis.Pass) []types.Type {
+	var local []types.Type
+	declared := map[ast.Expr]bool{}
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			if spec, ok := node.(*ast.TypeSpec); ok {
+				declared[spec.Type] = true
+			}
+
+			return true
+		})
+	}
+
+	for _, obj := range pass.TypesInfo.Defs {
+		typeName, ok := obj.(*types.TypeName)
+		if !ok || typeName.IsAlias() || typeName.Parent() == pass.Pkg.Scope() {
+			continue
+		}
+
+		if _, ok := typeName.Type().(*types.TypeParam); !ok {
+			local = append(local, typeName.Type())
+		}
+	}
+
+	for x, tv := range pass.TypesInfo.Types {
+		if _, ok := tv.Type.(*types.Struct); ok && tv.IsType() && !declared[x] {
+			local = append(local, tv.Type)
+		}
+	}
+
+	qualifier := types.RelativeTo(pass.Pkg)
+	sort.Slice(local, func(i, j int) bool {
+		return types.TypeString(local[i], qualifier) < types.TypeString(local[j], qualifier)
+	})
+
+	return local
+}
+
+// isInterfaceClosed checks if every implementation of the method that the interface can hold is
+// visible to us. A method with an unexported name can only be declared by the package of the
+// interface, and a type from another package can't override it, even when it embeds one of our types.
+// Otherwise, the interface must be declared by this package and be kept out of its exported API, so
+// other packages can't implement it, and it must never be converted from another interface, which
+// can hold the types of any package.
+func isInterfaceClosed(pass *analysis.Pass, ty types.Type, method string) bool {
+	if ty == nil {
+		return false
+	}
+
+	if _, ok := ty.Underlying().(*types.Interface); !ok {
+		return false
+	}
+
+	if !token.IsExported(method) {
+		return true
+	}
+
+	named, ok := ty.(*types.Named)
+	if !ok || named.Obj().Pkg() != pass.Pkg || named.Obj().Exported() {
+		return false
+	}
+
+	return !isInExportedAPI(pass, named) && !isConvertedFromInterface(pass, named)
+}
+
+// isConvertedFromInterface checks if a value of another interface is converted to the interface,
+// either by a type assertion e.g. `x.(runner)` where x is an any, or by assigning it.
+func isConvertedFromInterface(pass *analysis.Pass, target types.Type) bool {
+	ssaInfo, ok := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+	if !ok {
+		return true
+	}
+
+	funcs := ssaInfo.SrcFuncs
+	if init := ssaInfo.Pkg.Func("init"); init != nil {
+		funcs = append(funcs[:len(funcs):len(funcs)], init)
+	}
+
+	for _, fn := range funcs {
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				switch instr := instr.(type) {
+				case *ssa.TypeAssert:
+					if types.Identical(instr.AssertedType, target) {
+						return true
+					}
+				case *ssa.ChangeInterface:
+					if types.Identical(instr.Type(), target) {
+						return true
+					}
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// isInExportedAPI checks if the type is used by the exported API of this package e.g. as a parameter
+// of an exported function, so other packages can pass their own types as it.
+func isInExportedAPI(pass *analysis.Pass, target types.Type) bool {
+	return visitExportedAPI(pass, func(ty types.Type) bool {
+		_, ok := ty.(*types.Named)
+		return ok && types.Identical(ty, target)
+	})
+}
+
+// isFieldInExportedAPI checks if the field is exported by a struct used by the exported API of this
+// package, so other packages can assign it.
+func isFieldInExportedAPI(pass *analysis.Pass, field *types.Var) bool {
+	if !field.Exported() {
+		return false
+	}
+
+	return visitExportedAPI(pass, func(ty types.Type) bool {
+		st, ok := ty.(*types.Struct)
+		if !ok {
+			return false
+		}
+
+		for i := 0; i < st.NumFields(); i++ {
+			if st.Field(i).Origin() == field.Origin() {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// visitExportedAPI calls found with every type used by the exported API of this package, until it
+// returns true. The fields of an embedded struct are promoted, so they're visited even when the
+// embedded field isn't exported.
+func visitExportedAPI(pass *analysis.Pass, found func(ty types.Type) bool) bool {
+	visited := map[types.Type]bool{}
+
+	var contains func(ty types.Type) bool
+	contains = func(ty types.Type) bool {
+		if ty == nil || visited[ty] {
+			return false
+		}
+
+		visited[ty] = true
+		if found(ty) {
+			return true
+		}
+
+		switch ty := ty.(type) {
+		case *types.Named:
+			if contains(ty.Underlying()) {
+				return true
+			}
+
+			for i := 0; i < ty.NumMethods(); i++ {
+				if ty.Method(i).Exported() && contains(ty.Method(i).Type()) {
+					return true
+				}
+			}
+		case *types.Pointer:
+			return contains(ty.Elem())
+		case *types.Slice:
+			return contains(ty.Elem())
+		case *types.Array:
+			return contains(ty.Elem())
+		case *types.Chan:
+			return contains(ty.Elem())
+		case *types.Map:
+			return contains(ty.Key()) || contains(ty.Elem())
+		case *types.Tuple:
+			for i := 0; i < ty.Len(); i++ {
+				if contains(ty.At(i).Type()) {
+					return true
+				}
+			}
+		case *types.Signature:
+			return contains(ty.Params()) || contains(ty.Results())
+		case *types.Struct:
+			for i := 0; i < ty.NumFields(); i++ {
+				field := ty.Field(i)
+				if (field.Exported() || field.Embedded()) && contains(field.Type()) {
+					return true
+				}
+			}
+		case *types.Interface:
+			for i := 0; i < ty.NumMethods(); i++ {
+				if ty.Method(i).Exported() && contains(ty.Method(i).Type()) {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+
+	scope := pass.Pkg.Scope()
+	for _, name := range scope.Names() {
+		if obj := scope.Lookup(name); obj.Exported() && contains(obj.Type()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getImplementingType gets either the type or the pointer to it, whichever implements the interface.
+// Generic types aren't instantiated yet, so we only check they have a method with each name, which
+// may include a few types that can't actually be converted to the interface.
+func getImplementingType(ty types.Type, iface *types.Interface) (types.Type, bool) {
+	ptr := types.NewPointer(ty)
+	if named, ok := ty.(*types.Named); ok && named.TypeParams().Len() > 0 {
+		for i := 0; i < iface.NumMethods(); i++ {
+			method := iface.Method(i)
+			if obj, _, _ := types.LookupFieldOrMethod(ptr, false, method.Pkg(), method.Name()); obj == nil {
+				return nil, false
+			}
+		}
+
+		return ty, true
+	}
+
+	switch {
+	case types.Implements(ty, iface):
+		return ty, true
+	case types.Implements(ptr, iface):
+		return ptr, true
+	default:
+		return nil, false
+	}
+}
+
+// isMethodSafe checks if the method of the concrete type, with the same name as the interface method,
+// is safe.
+func isMethodSafe(pass *analysis.Pass, ty types.Type, method *types.Func) bool {
+	obj, _, _ := types.LookupFieldOrMethod(ty, true, method.Pkg(), method.Name())
+	if obj == nil {
+		return false
+	}
+
+	if iface, ok := getMethodInterface(obj); ok && !types.IsInterface(ty) {
+		// The method is promoted from an embedded interface, so the call is forwarded to whatever the
+		// interface holds.
+		return isForwardedMethodSafe(pass, iface, method)
+	}
+
+	return isObjectSafe(pass, obj)
+}
+
+// getMethodInterface gets the interface that declares the method, when it's an interface method.
+func getMethodInterface(obj types.Object) (*types.Interface, bool) {
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return nil, false
+	}
+
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return nil, false
+	}
+
+	iface, ok := recv.Type().Underlying().(*types.Interface)
+	return iface, ok
+}
+
+// isForwardedMethodSafe checks if the method is safe in every type that implements the interface
+// itself. The types that embed the interface only forward the call to one of those types.
+func isForwardedMethodSafe(pass *analysis.Pass, iface *types.Interface, method *types.Func) bool {
+	if !isInterfaceClosed(pass, method.Type().(*types.Signature).Recv().Type(), method.Name()) {
+		return false
+	}
+
+	found := false
+	for _, ty := range getImplementations(pass, iface) {
+		obj, _, _ := types.LookupFieldOrMethod(ty, true, method.Pkg(), method.Name())
+		if obj == nil {
+			return false
+		}
+
+		if _, ok := getMethodInterface(obj); ok {
+			continue
+		}
+
+		if !isObjectSafe(pass, obj) {
+			return false
+		}
+
+		found = true
+	}
+
+	return found
+}
+
+// getUnsafeImplementations gets the types whose implementation of the method is unsafe.
+func getUnsafeImplementations(pass *analysis.Pass, impls []types.Type, method *types.Func) []types.Type {
+	var unsafe []types.Type
+	for _, ty := range impls {
+		if !isMethodSafe(pass, ty, method) {
+			unsafe = append(unsafe, ty)
+		}
+	}
+
+	return unsafe
+}
+
+// formatTypes lists the types, qualified relative to this package. Generic types are listed by name,
+// without their type parameters.
+func formatTypes(pass *analysis.Pass, tys []types.Type) string {
+	qualifier := types.RelativeTo(pass.Pkg)
+	names := make([]string, 0, len(tys))
+	for _, ty := range tys {
+		named, ok := ty.(*types.Named)
+		if !ok || named.TypeParams().Len() == 0 || named.TypeArgs().Len() > 0 {
+			names = append(names, types.TypeString(ty, qualifier))
+			continue
+		}
+
+		name := named.Obj().Name()
+		if pkg := qualifier(named.Obj().Pkg()); pkg != "" {
+			name = pkg + "." + name
+		}
+
+		names = append(names, name)
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,421 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// isFuncCollection checks if the type is a slice, array, map or channel of functions.
+func isFuncCollection(ty types.Type) bool {
+	if ty == nil {
+		return false
+	}
+
+	var elem types.Type
+	switch ty := ty.Underlying().(type) {
+	case *types.Slice:
+		elem = ty.Elem()
+	case *types.Array:
+		elem = ty.Elem()
+	case *types.Map:
+		elem = ty.Elem()
+	case *types.Chan:
+		elem = ty.Elem()
+	default:
+		return false
+	}
+
+	_, ok := elem.Underlying().(*types.Signature)
+	return ok
+}
+
+// getReadCollection gets the collection an element is read from, either by indexing it e.g.
+// `table[key]` or by receiving from it e.g. `<-jobs`.
+func getReadCollection(pass *analysis.Pass, x ast.Expr) (ast.Expr, bool) {
+	var collection ast.Expr
+	switch x := astutil.Unparen(x).(type) {
+	case *ast.IndexExpr:
+		collection = x.X
+	case *ast.UnaryExpr:
+		if x.Op != token.ARROW {
+			return nil, false
+		}
+
+		collection = x.X
+	default:
+		return nil, false
+	}
+
+	return collection, isFuncCollection(pass.TypesInfo.TypeOf(collection))
+}
+
+// getRangeCollection gets the collection the variable ranges over e.g. `handlers` in
+// `for _, f := range handlers`, as long as the loop doesn't reassign it.
+func getRangeCollection(pass *analysis.Pass, v *types.Var) (ast.Expr, bool) {
+	var collection ast.Expr
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			rangeStmt, ok := node.(*ast.RangeStmt)
+			if !ok || rangeStmt.Tok != token.DEFINE || !isFuncCollection(pass.TypesInfo.TypeOf(rangeStmt.X)) {
+				return collection == nil
+			}
+
+			// Ranging over a channel gives the elements as the key.
+			elem := rangeStmt.Value
+			if _, ok := pass.TypesInfo.TypeOf(rangeStmt.X).Underlying().(*types.Chan); ok {
+				elem = rangeStmt.Key
+			}
+
+			if id, ok := elem.(*ast.Ident); ok && pass.TypesInfo.Defs[id] == v && !isVarReassigned(pass, rangeStmt.Body, v) {
+				collection = rangeStmt.X
+			}
+
+			return collection == nil
+		})
+	}
+
+	return collection, collection != nil
+}
+
+// getCollectionVar gets the variable or field holding the collection e.g. `handlers` in
+// `s.handlers`.
+func getCollectionVar(pass *analysis.Pass, x ast.Expr) (*types.Var, bool) {
+	var v *types.Var
+	switch x := astutil.Unparen(x).(type) {
+	case *ast.Ident:
+		v, _ = pass.TypesInfo.ObjectOf(x).(*types.Var)
+	case *ast.SelectorExpr:
+		v, _ = pass.TypesInfo.ObjectOf(x.Sel).(*types.Var)
+	}
+
+	if v == nil || !isFuncCollection(v.Type()) {
+		return nil, false
+	}
+
+	return v.Origin(), true
+}
+
+// isCollectionSafe checks if every function the collection can hold is safe. A collection held by a
+// variable or field is only as safe as every element stored or sent into it in this package, so an
+// exported one that other packages can store their own functions in is never safe. visiting holds the
+// collections we are already checking.
+func isCollectionSafe(pass *analysis.Pass, x ast.Expr, visiting map[*types.Var]bool) bool {
+	v, ok := getCollectionVar(pass, x)
+	if !ok {
+		var elements []ast.Expr
+		addCollectionElements(pass, nil, x, func(value ast.Expr) {
+			elements = append(elements, value)
+		})
+
+		return areElementsSafe(pass, elements, visiting)
+	}
+
+	if v.Pkg() != pass.Pkg || (isPackageVar(v) && v.Exported()) || (v.IsField() && isFieldInExportedAPI(pass, v)) {
+		return false
+	}
+
+	if visiting[v] {
+		// The other elements stored in the cycle decide whether the collection is safe.
+		return true
+	}
+
+	if visiting == nil {
+		visiting = map[*types.Var]bool{}
+	}
+
+	visiting[v] = true
+	defer delete(visiting, v)
+
+	return areElementsSafe(pass, getCollectionElements(pass, v), visiting)
+}
+
+func areElementsSafe(pass *analysis.Pass, elements []ast.Expr, visiting map[*types.Var]bool) bool {
+	for _, elem := range elements {
+		if elem == nil || !isFuncSafeVisiting(pass, elem, visiting) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getCollectionElements finds every function this package stores in the collection held by the
+// variable or field, through composite literals, `append` calls, index assignments and channel
+// sends. A nil element is one we can't follow e.g. the collection is a parameter, its address is
+// taken, or it's aliased.
+func getCollectionElements(pass *analysis.Pass, v *types.Var) []ast.Expr {
+	var elements []ast.Expr
+	add := func(value ast.Expr) {
+		elements = append(elements, value)
+	}
+
+	isVar := func(x ast.Expr) bool {
+		cur, ok := getCollectionVar(pass, x)
+		return ok && cur == v
+	}
+
+	if isCollectionAliased(pass, v) {
+		add(nil)
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.AssignStmt:
+				for i, lhs := range node.Lhs {
+					var value ast.Expr
+					if len(node.Lhs) == len(node.Rhs) {
+						value = node.Rhs[i]
+					}
+
+					if index, ok := astutil.Unparen(lhs).(*ast.IndexExpr); ok && isVar(index.X) {
+						add(value)
+					} else if isVar(lhs) {
+						addCollectionElements(pass, v, value, add)
+					}
+				}
+			case *ast.ValueSpec:
+				for i, name := range node.Names {
+					if pass.TypesInfo.Defs[name] != v || len(node.Values) == 0 {
+						continue
+					}
+
+					var value ast.Expr
+					if len(node.Names) == len(node.Values) {
+						value = node.Values[i]
+					}
+
+					addCollectionElements(pass, v, value, add)
+				}
+			case *ast.CompositeLit:
+				addCompositeLitFields(pass, node, func(field *types.Var, value ast.Expr) {
+					if field.Origin() == v && value != nil {
+						addCollectionElements(pass, v, value, add)
+					}
+				})
+			case *ast.SendStmt:
+				if isVar(node.Chan) {
+					add(node.Value)
+				}
+			case *ast.CallExpr:
+				// copy(dst, src) stores the elements of src in dst.
+				if isBuiltin(pass, node.Fun, "copy") && len(node.Args) == 2 && isVar(node.Args[0]) {
+					addCollectionElements(pass, v, node.Args[1], add)
+				}
+			case *ast.UnaryExpr:
+				if node.Op != token.AND {
+					break
+				}
+
+				// The address of an element can be used to store anything in it.
+				if index, ok := astutil.Unparen(node.X).(*ast.IndexExpr); ok && isVar(index.X) || isVar(node.X) {
+					add(nil)
+				}
+			case *ast.RangeStmt:
+				if node.Tok == token.DEFINE && (isVar(node.Key) || isVar(node.Value)) {
+					add(nil)
+				}
+			case *ast.FuncType:
+				// The arguments and results of a function can hold anything.
+				for _, fields := range []*ast.FieldList{node.Params, node.Results} {
+					if fields == nil {
+						continue
+					}
+
+					for _, field := range fields.List {
+						for _, name := range field.Names {
+							if pass.TypesInfo.Defs[name] == v {
+								add(nil)
+							}
+						}
+					}
+				}
+			}
+
+			return true
+		})
+	}
+
+	return elements
+}
+
+// isCollectionAliased checks if the collection held by the variable or field is used other than by
+// indexing it, ranging over it, sending to or receiving from it, or passing it to a builtin like
+// `len`. Any other use e.g. `a := handlers` or `register(handlers)` shares the collection with code
+// that can store anything in it. Storing the collection back in itself e.g. `v = append(v, f)` is
+// fine.
+func isCollectionAliased(pass *analysis.Pass, v *types.Var) bool {
+	isVar := func(x ast.Expr) bool {
+		cur, ok := getCollectionVar(pass, x)
+		return ok && cur == v
+	}
+
+	allowed := map[ast.Expr]bool{}
+	allow := func(x ast.Expr) {
+		if x != nil && isVar(x) {
+			allowed[astutil.Unparen(x)] = true
+		}
+	}
+
+	var allowSelf func(x ast.Expr)
+	allowSelf = func(x ast.Expr) {
+		switch x := astutil.Unparen(x).(type) {
+		case *ast.SliceExpr:
+			allow(x.X)
+		case *ast.CallExpr:
+			if !isBuiltin(pass, x.Fun, "append") {
+				return
+			}
+
+			for _, arg := range x.Args {
+				allow(arg)
+				allowSelf(arg)
+			}
+		}
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.IndexExpr:
+				allow(node.X)
+			case *ast.RangeStmt:
+				allow(node.X)
+			case *ast.SendStmt:
+				allow(node.Chan)
+			case *ast.UnaryExpr:
+				if node.Op == token.ARROW {
+					allow(node.X)
+				}
+			case *ast.BinaryExpr:
+				if node.Op == token.EQL || node.Op == token.NEQ {
+					allow(node.X)
+					allow(node.Y)
+				}
+			case *ast.KeyValueExpr:
+				// The field of a struct literal.
+				allow(node.Key)
+			case *ast.CallExpr:
+				for _, name := range []string{"len", "cap", "close", "delete", "copy"} {
+					if !isBuiltin(pass, node.Fun, name) {
+						continue
+					}
+
+					for _, arg := range node.Args {
+						allow(arg)
+					}
+				}
+			case *ast.AssignStmt:
+				for i, lhs := range node.Lhs {
+					if !isVar(lhs) {
+						continue
+					}
+
+					allow(lhs)
+					if len(node.Lhs) == len(node.Rhs) {
+						allowSelf(node.Rhs[i])
+					}
+				}
+			}
+
+			return true
+		})
+	}
+
+	aliased := false
+	var visit func(node ast.Node) bool
+	visit = func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			if obj, ok := pass.TypesInfo.Uses[node.Sel].(*types.Var); ok && obj.Origin() == v {
+				aliased = aliased || !allowed[node]
+			}
+
+			// The selected identifier is checked with the selector as a whole.
+			ast.Inspect(node.X, visit)
+
+			return false
+		case *ast.Ident:
+			if obj, ok := pass.TypesInfo.Uses[node].(*types.Var); ok && obj.Origin() == v {
+				aliased = aliased || !allowed[node]
+			}
+		}
+
+		return !aliased
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, visit)
+	}
+
+	return aliased
+}
+
+// addCollectionElements adds the elements of a collection assigned to v. Reading v itself e.g.
+// `v = v[1:]` adds nothing new.
+func addCollectionElements(pass *analysis.Pass, v *types.Var, x ast.Expr, add func(ast.Expr)) {
+	if x == nil {
+		add(nil)
+		return
+	}
+
+	if cur, ok := getCollectionVar(pass, x); ok && cur == v {
+		return
+	}
+
+	switch x := astutil.Unparen(x).(type) {
+	case *ast.CompositeLit:
+		for _, elt := range x.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+
+			add(elt)
+		}
+	case *ast.UnaryExpr:
+		if x.Op != token.AND {
+			add(nil)
+			return
+		}
+
+		addCollectionElements(pass, v, x.X, add)
+	case *ast.SliceExpr:
+		addCollectionElements(pass, v, x.X, add)
+	case *ast.Ident:
+		if x.Name != "nil" || pass.TypesInfo.Uses[x] != types.Universe.Lookup("nil") {
+			add(nil)
+		}
+	case *ast.CallExpr:
+		switch {
+		case isBuiltin(pass, x.Fun, "make"):
+		case isBuiltin(pass, x.Fun, "append") && len(x.Args) > 0:
+			addCollectionElements(pass, v, x.Args[0], add)
+			if x.Ellipsis.IsValid() {
+				addCollectionElements(pass, v, x.Args[len(x.Args)-1], add)
+				return
+			}
+
+			for _, arg := range x.Args[1:] {
+				add(arg)
+			}
+		default:
+			add(nil)
+		}
+	default:
+		add(nil)
+	}
+}
+
+func isBuiltin(pass *analysis.Pass, fun ast.Expr, name string) bool {
+	id, ok := astutil.Unparen(fun).(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	builtin, ok := pass.TypesInfo.Uses[id].(*types.Builtin)
+	return ok && builtin.Name() == name
+}
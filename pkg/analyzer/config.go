@@ -0,0 +1,145 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// launcherKind describes how a call to a configured launcher is checked.
+type launcherKind int
+
+const (
+	notLauncher launcherKind = iota
+	// safeLauncher is a function that always recovers for the callbacks it runs, so calls to it are
+	// never reported.
+	safeLauncher
+	// unsafeLauncher is a function that runs its callbacks on a new Goroutine, so each callback is
+	// checked like the function of a go statement.
+	unsafeLauncher
+	// forbiddenLauncher is a function that must never be called.
+	forbiddenLauncher
+)
+
+// funcList is a flag holding fully qualified function or method names e.g.
+// `(*golang.org/x/sync/errgroup.Group).Go`. It can be set to a comma separated list, or set more
+// than once.
+type funcList map[string]bool
+
+func (l funcList) String() string {
+	names := make([]string, 0, len(l))
+	for name := range l {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return strings.Join(names, ",")
+}
+
+func (l funcList) Set(value string) error {
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			l[name] = true
+		}
+	}
+
+	return nil
+}
+
+// config is the file passed to the config flag.
+type config struct {
+	SafeLaunchers      []string `json:"safeLaunchers"`
+	UnsafeLaunchers    []string `json:"unsafeLaunchers"`
+	ForbiddenLaunchers []string `json:"forbiddenLaunchers"`
+	TerminatingFuncs   []string `json:"terminatingFuncs"`
+}
+
+//nolint:gochecknoglobals
+var (
+	configMu sync.Mutex
+	// loadedConfigPath is the config file the launchers were last loaded from, so every package
+	// doesn't read it again.
+	loadedConfigPath string
+	loadedConfig     config
+)
+
+// loadConfig reads the config file, if one is set. The packages are analyzed in parallel, so the
+// file is only read by the first one.
+func loadConfig() (config, error) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	if configPath == "" {
+		return config{}, nil
+	}
+
+	if configPath == loadedConfigPath {
+		return loadedConfig, nil
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return config{}, fmt.Errorf("Failed to read config %q: %w", configPath, err)
+	}
+
+	var cfg config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return config{}, fmt.Errorf("Failed to parse config %q: %w", configPath, err)
+	}
+
+	loadedConfigPath, loadedConfig = configPath, cfg
+
+	return cfg, nil
+}
+
+// launchers holds the kind of every configured launcher, from both the flags and the config file.
+type launchers map[string]launcherKind
+
+// getKind gets the kind of launcher the function was configured as.
+func (l launchers) getKind(fn types.Object) launcherKind {
+	tFn, ok := fn.(*types.Func)
+	if !ok {
+		return notLauncher
+	}
+
+	return l[tFn.FullName()]
+}
+
+func getLaunchers() (launchers, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	out := launchers{}
+	add := func(kind launcherKind, names ...string) {
+		for _, name := range names {
+			out[name] = kind
+		}
+	}
+
+	add(safeLauncher, cfg.SafeLaunchers...)
+	add(unsafeLauncher, cfg.UnsafeLaunchers...)
+	add(forbiddenLauncher, cfg.ForbiddenLaunchers...)
+
+	// The flags take precedence over the config file.
+	for _, flag := range []struct {
+		kind  launcherKind
+		names funcList
+	}{
+		{safeLauncher, safeLauncherFlag},
+		{unsafeLauncher, unsafeLauncherFlag},
+		{forbiddenLauncher, forbiddenLauncherFlag},
+	} {
+		for name := range flag.names {
+			out[name] = flag.kind
+		}
+	}
+
+	return out, nil
+}
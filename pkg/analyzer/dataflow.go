@@ -0,0 +1,392 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/ssa"
+)
+
+// dataflow resolves the values that reach a Goroutine using the SSA form of the package. It lets us
+// follow local variables, pointers and interfaces back to the function, method or struct literal
+// they were assigned from, which the syntax alone can't tell us.
+type dataflow struct {
+	pass *analysis.Pass
+	// goInstrs maps the position of the `go` keyword to its SSA instruction.
+	goInstrs map[token.Pos]*ssa.Go
+	// callInstrs maps the position of the opening parenthesis of a call to its SSA instruction.
+	callInstrs map[token.Pos]*ssa.Call
+	// visiting tracks the values we are resolving, so cycles through phi nodes terminate.
+	visiting map[ssa.Value]bool
+}
+
+func newDataflow(pass *analysis.Pass) (*dataflow, error) {
+	ssaInfo, ok := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
+	if !ok {
+		return nil, fmt.Errorf("Expected buildssa.Analyzer to be an *buildssa.SSA, but got %T", pass.ResultOf[buildssa.Analyzer])
+	}
+
+	d := &dataflow{
+		pass:       pass,
+		goInstrs:   map[token.Pos]*ssa.Go{},
+		callInstrs: map[token.Pos]*ssa.Call{},
+		visiting:   map[ssa.Value]bool{},
+	}
+
+	for _, fn := range ssaInfo.SrcFuncs {
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				switch instr := instr.(type) {
+				case *ssa.Go:
+					d.goInstrs[instr.Pos()] = instr
+				case *ssa.Call:
+					d.callInstrs[instr.Pos()] = instr
+				}
+			}
+		}
+	}
+
+	return d, nil
+}
+
+// isGoStmtSafe checks if every definition reaching the function called by the Goroutine is safe.
+func (d *dataflow) isGoStmtSafe(goStmt *ast.GoStmt) bool {
+	goInstr, ok := d.goInstrs[goStmt.Go]
+	if !ok {
+		return false
+	}
+
+	return d.isCallSafe(goInstr.Common())
+}
+
+// isCallArgSafe checks if every definition reaching the i-th argument of the call is a safe function.
+func (d *dataflow) isCallArgSafe(call *ast.CallExpr, i int) bool {
+	callInstr, ok := d.callInstrs[call.Lparen]
+	if !ok || callInstr.Common().IsInvoke() {
+		return false
+	}
+
+	args := callInstr.Common().Args
+	if _, ok := callInstr.Common().Value.(*ssa.Function); ok && callInstr.Common().Signature().Recv() != nil {
+		// Static method calls pass the receiver as the first argument.
+		i++
+	}
+
+	if i >= len(args) {
+		return false
+	}
+
+	return d.isValueSafe(args[i])
+}
+
+// getInvokedTypes gets the dynamic types of the interface the Goroutine calls a method of, when
+// they can be traced.
+func (d *dataflow) getInvokedTypes(goStmt *ast.GoStmt) ([]types.Type, bool) {
+	goInstr, ok := d.goInstrs[goStmt.Go]
+	if !ok || !goInstr.Common().IsInvoke() {
+		return nil, false
+	}
+
+	return d.concreteTypes(goInstr.Common().Value)
+}
+
+func (d *dataflow) isCallSafe(call *ssa.CallCommon) bool {
+	if call.IsInvoke() {
+		tys, ok := d.concreteTypes(call.Value)
+		if !ok {
+			return false
+		}
+
+		for _, ty := range tys {
+			method, _, _ := types.LookupFieldOrMethod(ty, true, call.Method.Pkg(), call.Method.Name())
+			if method == nil || !d.isObjectSafe(method) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if callee := call.StaticCallee(); callee != nil && callee.Object() != nil && isDelegatingFunc(d.pass, callee.Object()) && len(call.Args) > 0 {
+		// The method only calls its receiver, which is passed as the first argument.
+		return d.isValueSafe(call.Args[0])
+	}
+
+	return d.isValueSafe(call.Value)
+}
+
+// isValueSafe checks if every definition of the function value v is safe.
+func (d *dataflow) isValueSafe(v ssa.Value) bool {
+	if d.visiting[v] {
+		// The other definitions reaching the cycle decide whether the value is safe.
+		return true
+	}
+
+	d.visiting[v] = true
+	defer delete(d.visiting, v)
+
+	switch v := v.(type) {
+	case *ssa.Function:
+		return d.isFunctionSafe(v)
+	case *ssa.MakeClosure:
+		fn, ok := v.Fn.(*ssa.Function)
+		if ok && fn.Synthetic != "" && fn.Object() != nil && isDelegatingFunc(d.pass, fn.Object()) && len(v.Bindings) == 1 {
+			// A method value of a method that only calls its receiver e.g. `Job(handle).Run`.
+			return d.isValueSafe(v.Bindings[0])
+		}
+
+		return ok && d.isFunctionSafe(fn)
+	case *ssa.Phi:
+		return d.allSafe(v.Edges, d.isValueSafe)
+	case *ssa.UnOp:
+		if v.Op != token.MUL {
+			return false
+		}
+
+		return d.isAddrSafe(v.X)
+	case *ssa.Field:
+		return d.isFieldSafe(v.X, v.Field)
+	case *ssa.Call:
+		// The function returned by a factory e.g. `h := newHandler()`.
+		callee := v.Call.StaticCallee()
+		return callee != nil && callee.Object() != nil && isFactorySafe(d.pass, callee.Object())
+	case *ssa.ChangeType:
+		// A conversion between function types e.g. `handlerFunc(f)`.
+		return d.isValueSafe(v.X)
+	case *ssa.TypeAssert:
+		return d.isValueSafe(v.X)
+	case *ssa.Extract:
+		// The value of a type assertion with a comma-ok, or a type switch.
+		assert, ok := v.Tuple.(*ssa.TypeAssert)
+		return ok && v.Index == 0 && d.isValueSafe(assert.X)
+	case *ssa.MakeInterface:
+		// Callbacks typed as any, e.g. a finalizer, are converted to an interface first.
+		return d.isValueSafe(v.X)
+	default:
+		return false
+	}
+}
+
+// isAddrSafe checks if every function stored at addr is safe.
+func (d *dataflow) isAddrSafe(addr ssa.Value) bool {
+	switch addr := addr.(type) {
+	case *ssa.Alloc:
+		stores, ok := getLocalStores(addr)
+		return ok && len(stores) > 0 && d.allSafe(storedValues(stores), d.isValueSafe)
+	case *ssa.FieldAddr:
+		return d.isAddrFieldSafe(addr.X, addr.Field)
+	default:
+		return false
+	}
+}
+
+// isFieldSafe checks if the function held in the given field of the struct value x is safe.
+func (d *dataflow) isFieldSafe(x ssa.Value, field int) bool {
+	switch x := x.(type) {
+	case *ssa.UnOp:
+		if x.Op != token.MUL {
+			return false
+		}
+
+		return d.isAddrFieldSafe(x.X, field)
+	case *ssa.Phi:
+		return d.allSafe(x.Edges, func(edge ssa.Value) bool {
+			return d.isFieldSafe(edge, field)
+		})
+	default:
+		return false
+	}
+}
+
+// isAddrFieldSafe checks if every function stored in the given field of the struct at addr is safe.
+// Storing a whole struct counts as storing each of its fields.
+func (d *dataflow) isAddrFieldSafe(addr ssa.Value, field int) bool {
+	if _, ok := addr.(*ssa.Alloc); !ok {
+		return false
+	}
+
+	stores, ok := getLocalStores(addr)
+	if !ok {
+		return false
+	}
+
+	defined := false
+	for _, store := range stores {
+		if _, ok := store.Val.(*ssa.Const); ok && isFieldStoredAfter(store, field) {
+			// A composite literal clears the struct before it sets the fields it declares.
+			continue
+		}
+
+		if !d.isFieldSafe(store.Val, field) {
+			return false
+		}
+
+		defined = true
+	}
+
+	for _, ref := range *addr.Referrers() {
+		fieldAddr, ok := ref.(*ssa.FieldAddr)
+		if !ok || fieldAddr.Field != field {
+			continue
+		}
+
+		stores, ok := getLocalStores(fieldAddr)
+		if !ok || !d.allSafe(storedValues(stores), d.isValueSafe) {
+			return false
+		}
+
+		defined = defined || len(stores) > 0
+	}
+
+	return defined
+}
+
+// concreteTypes gets the dynamic types the interface value v may hold.
+func (d *dataflow) concreteTypes(v ssa.Value) ([]types.Type, bool) {
+	if d.visiting[v] {
+		return nil, true
+	}
+
+	d.visiting[v] = true
+	defer delete(d.visiting, v)
+
+	switch v := v.(type) {
+	case *ssa.MakeInterface:
+		return []types.Type{v.X.Type()}, true
+	case *ssa.ChangeInterface:
+		return d.concreteTypes(v.X)
+	case *ssa.Phi:
+		var out []types.Type
+		for _, edge := range v.Edges {
+			tys, ok := d.concreteTypes(edge)
+			if !ok {
+				return nil, false
+			}
+
+			out = append(out, tys...)
+		}
+
+		return out, true
+	case *ssa.UnOp:
+		alloc, ok := v.X.(*ssa.Alloc)
+		if v.Op != token.MUL || !ok {
+			return nil, false
+		}
+
+		stores, ok := getLocalStores(alloc)
+		if !ok || len(stores) == 0 {
+			return nil, false
+		}
+
+		var out []types.Type
+		for _, store := range stores {
+			tys, ok := d.concreteTypes(store.Val)
+			if !ok {
+				return nil, false
+			}
+
+			out = append(out, tys...)
+		}
+
+		return out, true
+	default:
+		return nil, false
+	}
+}
+
+// isFunctionSafe checks if a function has a recover, either through its facts or its syntax.
+func (d *dataflow) isFunctionSafe(fn *ssa.Function) bool {
+	if obj := fn.Object(); obj != nil {
+		return d.isObjectSafe(obj)
+	}
+
+	if lit, ok := fn.Syntax().(*ast.FuncLit); ok {
+		return isFuncLitSafe(d.pass, lit)
+	}
+
+	return false
+}
+
+func (d *dataflow) isObjectSafe(obj types.Object) bool {
+	return isObjectSafe(d.pass, obj)
+}
+
+func (d *dataflow) allSafe(values []ssa.Value, isSafe func(ssa.Value) bool) bool {
+	for _, v := range values {
+		if !isSafe(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getLocalStores gets every instruction that stores a value at addr. It fails if addr is used in
+// any other way e.g. captured by a closure, passed to a call, or stored itself, since the value can
+// then be written through an alias we can't follow. Taking the address of a field of addr is fine,
+// as the fields are checked separately.
+func getLocalStores(addr ssa.Value) ([]*ssa.Store, bool) {
+	refs := addr.Referrers()
+	if refs == nil {
+		return nil, false
+	}
+
+	var stores []*ssa.Store
+	for _, ref := range *refs {
+		switch ref := ref.(type) {
+		case *ssa.Store:
+			if ref.Addr != addr {
+				return nil, false
+			}
+
+			stores = append(stores, ref)
+		case *ssa.UnOp:
+			if ref.Op != token.MUL {
+				return nil, false
+			}
+		case *ssa.FieldAddr, *ssa.DebugRef:
+		default:
+			return nil, false
+		}
+	}
+
+	return stores, true
+}
+
+func storedValues(stores []*ssa.Store) []ssa.Value {
+	values := make([]ssa.Value, 0, len(stores))
+	for _, store := range stores {
+		values = append(values, store.Val)
+	}
+
+	return values
+}
+
+// isFieldStoredAfter checks if the given field of the struct written by store is overwritten
+// later in the same block.
+func isFieldStoredAfter(store *ssa.Store, field int) bool {
+	instrs := store.Block().Instrs
+	for i, instr := range instrs {
+		if instr != store {
+			continue
+		}
+
+		for _, next := range instrs[i+1:] {
+			next, ok := next.(*ssa.Store)
+			if !ok {
+				continue
+			}
+
+			fieldAddr, ok := next.Addr.(*ssa.FieldAddr)
+			if ok && fieldAddr.X == store.Addr && fieldAddr.Field == field {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// annotateDelegatingMethods marks the methods of named function types, that only call their receiver
+// e.g. `func (j Job) Run() { j() }`. Like `http.HandlerFunc`, the method is only as safe as the
+// function it's called on.
+func annotateDelegatingMethods(pass *analysis.Pass) error {
+	for fn, fdecl := range getFuncDecls(pass) {
+		if doesMethodDelegateToReceiver(pass, fdecl) {
+			pass.ExportObjectFact(fn, new(delegatesToReceiverFact))
+		}
+	}
+
+	return nil
+}
+
+// doesMethodDelegateToReceiver checks if every statement of the method calls its function-typed
+// receiver, either on its own or in a return statement e.g. `return j(ctx)`. The arguments of the
+// call are evaluated by the method, outside the receiver, so they can't panic.
+func doesMethodDelegateToReceiver(pass *analysis.Pass, fdecl *ast.FuncDecl) bool {
+	recv := getFuncSignature(pass, fdecl).Recv()
+	if recv == nil || len(fdecl.Body.List) == 0 {
+		return false
+	}
+
+	if _, ok := recv.Type().Underlying().(*types.Signature); !ok || isVarReassigned(pass, fdecl.Body, recv) {
+		return false
+	}
+
+	isRecvCall := func(x ast.Expr) bool {
+		call, ok := astutil.Unparen(x).(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+
+		id, ok := astutil.Unparen(call.Fun).(*ast.Ident)
+		return ok && pass.TypesInfo.Uses[id] == recv && !mayCallOperandsPanic(pass, call)
+	}
+
+	for _, stmt := range fdecl.Body.List {
+		switch stmt := stmt.(type) {
+		case *ast.ExprStmt:
+			if !isRecvCall(stmt.X) {
+				return false
+			}
+		case *ast.ReturnStmt:
+			if len(stmt.Results) != 1 || !isRecvCall(stmt.Results[0]) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// isDelegatingMethod checks if the selector calls a method that only calls its receiver, so the
+// receiver decides whether it's safe e.g. `Job(handle).Run`.
+func isDelegatingMethod(pass *analysis.Pass, sel *ast.SelectorExpr) bool {
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
+		return false
+	}
+
+	return isDelegatingFunc(pass, selection.Obj())
+}
+
+func isDelegatingFunc(pass *analysis.Pass, fn types.Object) bool {
+	origin, ok := getFunctionOrigin(fn)
+
+	return ok && pass.ImportObjectFact(origin, &delegatesToReceiverFact{})
+}
@@ -0,0 +1,161 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// annotateUnsafeSpawns marks the functions that start a Goroutine without a defer recover, either
+// directly or through a function they call. It's only needed to report the calls to those functions
+// from other packages, so it's skipped unless checkDependencies is set.
+func annotateUnsafeSpawns(pass *analysis.Pass, l launchers) error {
+	if !checkDependencies || isStandardLibrary(pass) {
+		return nil
+	}
+
+	df, err := newDataflow(pass)
+	if err != nil {
+		return err
+	}
+
+	funcDecls := getFuncDecls(pass)
+	obligated := getObligatedGoStmts(pass, funcDecls)
+	markUntilFixpoint(getFuncDecls(pass), func(fn types.Object, fdecl *ast.FuncDecl) bool {
+		fact, ok := getUnsafeSpawn(pass, df, l, fdecl, funcDecls, obligated)
+		if ok {
+			pass.ExportObjectFact(fn, fact)
+		}
+
+		return ok
+	})
+
+	return nil
+}
+
+// isStandardLibrary checks if the package is part of the standard library. It starts Goroutines of its
+// own e.g. for the garbage collector, which we can't add a recover to. The first element of a standard
+// library path never has a dot, but neither do the paths of some other packages e.g. in GOPATH, so we
+// also check that the package is found in GOROOT.
+func isStandardLibrary(pass *analysis.Pass) bool {
+	path := pass.Pkg.Path()
+	if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+		return false
+	}
+
+	pkg, err := build.Import(path, "", build.FindOnly)
+
+	return err == nil && pkg.Goroot
+}
+
+// getUnsafeSpawn finds the first Goroutine without a defer recover that the function starts, either
+// directly or through a function it calls.
+func getUnsafeSpawn(pass *analysis.Pass, df *dataflow, l launchers, fdecl *ast.FuncDecl, funcDecls map[types.Object]*ast.FuncDecl, obligated map[*ast.GoStmt]bool) (*spawnsUnsafeGoroutineFact, bool) {
+	var found *spawnsUnsafeGoroutineFact
+	ast.Inspect(fdecl.Body, func(node ast.Node) bool {
+		if found != nil {
+			return false
+		}
+
+		switch node := node.(type) {
+		case *ast.GoStmt:
+			if obligated[node] || isGoStmtSafe(pass, df, node, funcDecls) {
+				return true
+			}
+
+			found = newSpawnsUnsafeGoroutineFact(pass, fdecl, node)
+		case *ast.CallExpr:
+			callee := typeutil.StaticCallee(pass.TypesInfo, node)
+			if callee == nil {
+				return true
+			}
+
+			for _, i := range getLauncherCallbacks(pass, l, node) {
+				if !isCallbackSafe(pass, df, node, i, funcDecls) {
+					found = newSpawnsUnsafeGoroutineFact(pass, fdecl, node.Args[i])
+					return false
+				}
+			}
+
+			origin, _ := getFunctionOrigin(callee)
+			if l.getKind(origin) == safeLauncher {
+				return true
+			}
+
+			fact := new(spawnsUnsafeGoroutineFact)
+			if pass.ImportObjectFact(origin, fact) {
+				found = fact
+			}
+		}
+
+		return found == nil
+	})
+
+	return found, found != nil
+}
+
+// newSpawnsUnsafeGoroutineFact records the Goroutine started by the node in fdecl.
+func newSpawnsUnsafeGoroutineFact(pass *analysis.Pass, fdecl *ast.FuncDecl, node ast.Node) *spawnsUnsafeGoroutineFact {
+	posn := pass.Fset.Position(node.Pos())
+	name := fmt.Sprintf("%s.%s", pass.Pkg.Name(), fdecl.Name.Name)
+	if fn, ok := pass.TypesInfo.Defs[fdecl.Name].(*types.Func); ok {
+		name = getFuncName(fn)
+	}
+
+	return &spawnsUnsafeGoroutineFact{
+		Func: name,
+		Site: fmt.Sprintf("%s:%d", filepath.Base(posn.Filename), posn.Line),
+	}
+}
+
+// isGoStmtSafe checks if the Goroutine recovers before anything can panic.
+func isGoStmtSafe(pass *analysis.Pass, df *dataflow, goStmt *ast.GoStmt, funcDecls map[types.Object]*ast.FuncDecl) bool {
+	if !isFuncSafe(pass, goStmt.Call.Fun) && !df.isGoStmtSafe(goStmt) {
+		return false
+	}
+
+	return getUnprotectedNode(pass, getGoroutineBody(pass, goStmt, funcDecls)) == nil
+}
+
+// validateDependencyCalls reports the calls to functions from other packages, that start a Goroutine
+// without a defer recover.
+func validateDependencyCalls(pass *analysis.Pass, l launchers) error {
+	if !checkDependencies {
+		return nil
+	}
+
+	inspector, ok := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	if !ok {
+		return fmt.Errorf("Expected inspect.Analyzer to be an *inspector.Inspector, but got %T", pass.ResultOf[inspect.Analyzer])
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.CallExpr)(nil), /* Find calls to dependencies */
+	}
+
+	inspector.Preorder(nodeFilter, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		callee := typeutil.StaticCallee(pass.TypesInfo, call)
+		if callee == nil || callee.Pkg() == nil || callee.Pkg() == pass.Pkg {
+			return
+		}
+
+		origin, _ := getFunctionOrigin(callee)
+		fact := new(spawnsUnsafeGoroutineFact)
+		if l.getKind(origin) == safeLauncher || !pass.ImportObjectFact(origin, fact) {
+			return
+		}
+
+		pass.Reportf(call.Pos(), "Call to `%s` starts a Goroutine without a defer recover in `%s` at %s", callee.FullName(), fact.Func, fact.Site)
+	})
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// getSelectedValue gets the value the selector reads from a composite literal, following the
+// fields it's promoted through e.g. `handle` in `outer{myStruct{f: handle}}.f`. For a method, it
+// gets the embedded value the method is called on e.g. `safeJob{}` in `outer{safeJob{}}.Run`. It
+// returns false when the chain goes through anything other than a literal, or a field the literal
+// leaves out.
+func getSelectedValue(pass *analysis.Pass, sel *ast.SelectorExpr) (ast.Expr, bool) {
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok {
+		return nil, false
+	}
+
+	path := selection.Index()
+	switch selection.Kind() {
+	case types.FieldVal:
+	case types.MethodVal:
+		// The last index is the method, rather than a field.
+		path = path[:len(path)-1]
+		if len(path) == 0 {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	x := sel.X
+	for _, i := range path {
+		if x, ok = getLiteralField(pass, x, i); !ok {
+			return nil, false
+		}
+	}
+
+	return x, true
+}
+
+// getLiteralField gets the value the struct literal sets the i-th field to. A pointer to a literal is
+// treated the same as the literal e.g. `&myStruct{}`.
+func getLiteralField(pass *analysis.Pass, x ast.Expr, i int) (ast.Expr, bool) {
+	x = astutil.Unparen(x)
+	if addr, ok := x.(*ast.UnaryExpr); ok && addr.Op == token.AND {
+		x = astutil.Unparen(addr.X)
+	}
+
+	clit, ok := x.(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+
+	st, ok := pass.TypesInfo.TypeOf(clit).Underlying().(*types.Struct)
+	if !ok || i >= st.NumFields() {
+		return nil, false
+	}
+
+	for j, elt := range clit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			if j == i {
+				return elt, true
+			}
+
+			continue
+		}
+
+		if key, ok := kv.Key.(*ast.Ident); ok && key.Name == st.Field(i).Name() {
+			return kv.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// isEmbeddedInterfaceMethodSafe checks the method of an embedded interface, that the selector calls
+// through the struct embedding it e.g. `o.Run()` where o embeds a Runner. When the interface is set by
+// a literal, we only check the type it's set to, otherwise we check every type that implements it.
+// It returns false for the second value, when the selector isn't a method of an embedded interface.
+func isEmbeddedInterfaceMethodSafe(pass *analysis.Pass, sel *ast.SelectorExpr) (bool, bool) {
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal || len(selection.Index()) < 2 {
+		return false, false
+	}
+
+	method, ok := selection.Obj().(*types.Func)
+	if !ok {
+		return false, false
+	}
+
+	iface, ok := getMethodInterface(method)
+	if !ok {
+		return false, false
+	}
+
+	if value, ok := getSelectedValue(pass, sel); ok {
+		if ty := pass.TypesInfo.TypeOf(value); ty != nil && !types.IsInterface(ty) {
+			return isMethodSafe(pass, ty, method), true
+		}
+	}
+
+	return isForwardedMethodSafe(pass, iface, method), true
+}
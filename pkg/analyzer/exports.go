@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// validateExports checks the functions exported to C by a `//export` directive, when checkExports is
+// set. They're called from C threads, where a panic aborts the whole process, so each one is checked
+// like the function of a go statement.
+func validateExports(pass *analysis.Pass) error {
+	if !checkExports {
+		return nil
+	}
+
+	inspector, ok := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	if !ok {
+		return fmt.Errorf("Expected inspect.Analyzer to be an *inspector.Inspector, but got %T", pass.ResultOf[inspect.Analyzer])
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil), /* Find exported functions */
+	}
+
+	inspector.Preorder(nodeFilter, func(node ast.Node) {
+		fdecl := node.(*ast.FuncDecl)
+		if fdecl.Body == nil || !isCgoExport(fdecl) {
+			return
+		}
+
+		name := fdecl.Name.Name
+		if unprotected := getUnprotectedNode(pass, fdecl); unprotected != nil {
+			pass.Reportf(fdecl.Name.Pos(), "Exported function `%s` can panic before its recover is deferred: `%s`", name, formatNode(pass, unprotected))
+			return
+		}
+
+		if fn := pass.TypesInfo.Defs[fdecl.Name]; fn != nil && isObjectSafe(pass, fn) {
+			return
+		}
+
+		if reason := getIneffectiveRecoverReason(pass, fdecl); reason != "" {
+			pass.Reportf(fdecl.Name.Pos(), "Exported function `%s` should have a defer recover: %s", name, reason)
+			return
+		}
+
+		pass.Reportf(fdecl.Name.Pos(), "Exported function `%s` should have a defer recover, since a panic on a C thread aborts the process", name)
+	})
+
+	return nil
+}
+
+// isCgoExport checks if the function has an `//export` directive, so it can be called from C.
+func isCgoExport(fdecl *ast.FuncDecl) bool {
+	if fdecl.Doc == nil {
+		return false
+	}
+
+	for _, comment := range fdecl.Doc.List {
+		if strings.HasPrefix(comment.Text, "//export ") {
+			return true
+		}
+	}
+
+	return false
+}
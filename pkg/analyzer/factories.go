@@ -0,0 +1,71 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// annotateSafeFactories marks the functions that always return a safe function e.g.
+// `func makeWorker(cfg config) func() { return func() { defer handlePanic(); work(cfg) } }`, so the
+// result can be started in a Goroutine e.g. `go makeWorker(cfg)()`. A factory can return the result
+// of another factory.
+func annotateSafeFactories(pass *analysis.Pass) error {
+	markUntilFixpoint(getFuncDecls(pass), func(fn types.Object, fdecl *ast.FuncDecl) bool {
+		if !returnsSafeFunc(pass, fdecl) {
+			return false
+		}
+
+		pass.ExportObjectFact(fn, new(returnsSafeFuncFact))
+		return true
+	})
+
+	return nil
+}
+
+// returnsSafeFunc checks if every return statement of the function returns a safe function.
+func returnsSafeFunc(pass *analysis.Pass, fdecl *ast.FuncDecl) bool {
+	results := getFuncSignature(pass, fdecl).Results()
+	if results.Len() != 1 {
+		return false
+	}
+
+	if _, ok := results.At(0).Type().Underlying().(*types.Signature); !ok {
+		return false
+	}
+
+	safe, found := true, false
+	ast.Inspect(fdecl.Body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			// The return statements of a function literal don't return from the factory.
+			return false
+		case *ast.ReturnStmt:
+			found = true
+			// A bare return gives back a named result, which can be set anywhere.
+			safe = safe && len(node.Results) == 1 && isFuncSafe(pass, node.Results[0])
+		}
+
+		return safe
+	})
+
+	return safe && found
+}
+
+// isFactoryCallSafe checks if the call is to a factory that always returns a safe function.
+func isFactoryCallSafe(pass *analysis.Pass, call *ast.CallExpr) bool {
+	callee := typeutil.StaticCallee(pass.TypesInfo, call)
+	if callee == nil {
+		return false
+	}
+
+	return isFactorySafe(pass, callee)
+}
+
+func isFactorySafe(pass *analysis.Pass, fn types.Object) bool {
+	origin, _ := getFunctionOrigin(fn)
+
+	return pass.ImportObjectFact(origin, &returnsSafeFuncFact{})
+}
@@ -0,0 +1,264 @@
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type isSafeFact struct{} // =>  *types.Func f is a function that won't panic
+
+func (*isSafeFact) AFact() {}
+
+func (*isSafeFact) String() string {
+	return "isSafe"
+}
+
+func (s *isSafeFact) GobDecode(data []byte) error {
+	if string(data) != "isSafe" {
+		return fmt.Errorf("invalid GOB data: %q", data)
+	}
+
+	s = &isSafeFact{}
+	return nil
+}
+
+func (*isSafeFact) GobEncode() ([]byte, error) {
+	return []byte("isSafe"), nil
+}
+
+type isRecoverHandlerFact struct{} // =>  *types.Func f calls recover, so deferring f stops a panic
+
+func (*isRecoverHandlerFact) AFact() {}
+
+func (*isRecoverHandlerFact) String() string {
+	return "isRecoverHandler"
+}
+
+func (*isRecoverHandlerFact) GobDecode(data []byte) error {
+	if string(data) != "isRecoverHandler" {
+		return fmt.Errorf("invalid GOB data: %q", data)
+	}
+
+	return nil
+}
+
+func (*isRecoverHandlerFact) GobEncode() ([]byte, error) {
+	return []byte("isRecoverHandler"), nil
+}
+
+type alwaysPanicsFact struct{} // =>  *types.Func f panics or exits the process on every path
+
+func (*alwaysPanicsFact) AFact() {}
+
+func (*alwaysPanicsFact) String() string {
+	return "alwaysPanics"
+}
+
+func (*alwaysPanicsFact) GobDecode(data []byte) error {
+	if string(data) != "alwaysPanics" {
+		return fmt.Errorf("invalid GOB data: %q", data)
+	}
+
+	return nil
+}
+
+func (*alwaysPanicsFact) GobEncode() ([]byte, error) {
+	return []byte("alwaysPanics"), nil
+}
+
+type onlySafeCallsFact struct{} // =>  *types.Func f only calls functions that won't panic
+
+func (*onlySafeCallsFact) AFact() {}
+
+func (*onlySafeCallsFact) String() string {
+	return "onlySafeCalls"
+}
+
+func (*onlySafeCallsFact) GobDecode(data []byte) error {
+	if string(data) != "onlySafeCalls" {
+		return fmt.Errorf("invalid GOB data: %q", data)
+	}
+
+	return nil
+}
+
+func (*onlySafeCallsFact) GobEncode() ([]byte, error) {
+	return []byte("onlySafeCalls"), nil
+}
+
+type noPanicFact struct{} // =>  *types.Func f can't panic
+
+func (*noPanicFact) AFact() {}
+
+func (*noPanicFact) String() string {
+	return "noPanic"
+}
+
+func (*noPanicFact) GobDecode(data []byte) error {
+	if string(data) != "noPanic" {
+		return fmt.Errorf("invalid GOB data: %q", data)
+	}
+
+	return nil
+}
+
+func (*noPanicFact) GobEncode() ([]byte, error) {
+	return []byte("noPanic"), nil
+}
+
+// typeParamObligationsFact => *types.Func f is a generic function that starts Goroutines, which are
+// only safe when f is instantiated with safe type arguments.
+type typeParamObligationsFact struct {
+	Obligations []typeParamObligation
+}
+
+func (*typeParamObligationsFact) AFact() {}
+
+func (f *typeParamObligationsFact) String() string {
+	obligations := make([]string, 0, len(f.Obligations))
+	for _, o := range f.Obligations {
+		obligations = append(obligations, o.String())
+	}
+
+	return fmt.Sprintf("typeParamObligations(%s)", strings.Join(obligations, ", "))
+}
+
+// spawnsParamFact => *types.Func f starts a Goroutine with the function passed as each of the
+// parameters, so each call to f is only safe when those arguments are safe.
+type spawnsParamFact struct {
+	Params []int
+}
+
+func (*spawnsParamFact) AFact() {}
+
+func (f *spawnsParamFact) String() string {
+	params := make([]string, 0, len(f.Params))
+	for _, i := range f.Params {
+		params = append(params, strconv.Itoa(i))
+	}
+
+	return fmt.Sprintf("spawnsParam(%s)", strings.Join(params, ", "))
+}
+
+// launcherFact => *types.Func f launches each of the parameters in a Goroutine that recovers, so
+// calling f is a safe way to start a Goroutine.
+type launcherFact struct {
+	Params []int
+}
+
+func (*launcherFact) AFact() {}
+
+func (f *launcherFact) String() string {
+	params := make([]string, 0, len(f.Params))
+	for _, i := range f.Params {
+		params = append(params, strconv.Itoa(i))
+	}
+
+	return fmt.Sprintf("launcher(%s)", strings.Join(params, ", "))
+}
+
+// spawnsUnsafeGoroutineFact => *types.Func f starts a Goroutine without a defer recover, either
+// directly or through a function it calls. Func and Site tell us where the Goroutine is started.
+type spawnsUnsafeGoroutineFact struct {
+	Func string
+	Site string
+}
+
+func (*spawnsUnsafeGoroutineFact) AFact() {}
+
+func (f *spawnsUnsafeGoroutineFact) String() string {
+	return fmt.Sprintf("spawnsUnsafeGoroutine(%s, %s)", f.Func, f.Site)
+}
+
+type safeFieldFact struct{} // =>  *types.Var f is a field where every function assigned to it is safe
+
+func (*safeFieldFact) AFact() {}
+
+func (*safeFieldFact) String() string {
+	return "safeField"
+}
+
+func (*safeFieldFact) GobDecode(data []byte) error {
+	if string(data) != "safeField" {
+		return fmt.Errorf("invalid GOB data: %q", data)
+	}
+
+	return nil
+}
+
+func (*safeFieldFact) GobEncode() ([]byte, error) {
+	return []byte("safeField"), nil
+}
+
+type returnsSafeFuncFact struct{} // =>  *types.Func f is a factory whose every result is a safe function
+
+func (*returnsSafeFuncFact) AFact() {}
+
+func (*returnsSafeFuncFact) String() string {
+	return "returnsSafeFunc"
+}
+
+func (*returnsSafeFuncFact) GobDecode(data []byte) error {
+	if string(data) != "returnsSafeFunc" {
+		return fmt.Errorf("invalid GOB data: %q", data)
+	}
+
+	return nil
+}
+
+func (*returnsSafeFuncFact) GobEncode() ([]byte, error) {
+	return []byte("returnsSafeFunc"), nil
+}
+
+type safeFuncVarFact struct{} // =>  *types.Var v is a package-level variable where every function assigned to it is safe
+
+func (*safeFuncVarFact) AFact() {}
+
+func (*safeFuncVarFact) String() string {
+	return "safeFuncVar"
+}
+
+func (*safeFuncVarFact) GobDecode(data []byte) error {
+	if string(data) != "safeFuncVar" {
+		return fmt.Errorf("invalid GOB data: %q", data)
+	}
+
+	return nil
+}
+
+func (*safeFuncVarFact) GobEncode() ([]byte, error) {
+	return []byte("safeFuncVar"), nil
+}
+
+type delegatesToReceiverFact struct{} // =>  *types.Func f is a method of a function type that only calls its receiver
+
+func (*delegatesToReceiverFact) AFact() {}
+
+func (*delegatesToReceiverFact) String() string {
+	return "delegatesToReceiver"
+}
+
+func (*delegatesToReceiverFact) GobDecode(data []byte) error {
+	if string(data) != "delegatesToReceiver" {
+		return fmt.Errorf("invalid GOB data: %q", data)
+	}
+
+	return nil
+}
+
+func (*delegatesToReceiverFact) GobEncode() ([]byte, error) {
+	return []byte("delegatesToReceiver"), nil
+}
+
+// terminatesProcessFact => *types.Func f can terminate the process e.g. by calling `log.Fatal`, either
+// directly or through a function it calls. Chain lists the calls that lead to it.
+type terminatesProcessFact struct {
+	Chain []string
+}
+
+func (*terminatesProcessFact) AFact() {}
+
+func (f *terminatesProcessFact) String() string {
+	return fmt.Sprintf("terminatesProcess(%s)", strings.Join(f.Chain, " -> "))
+}
@@ -0,0 +1,188 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// fieldAssignments holds the values assigned to the function fields of the structs, by this package.
+// A nil value is an assignment we can't follow e.g. taking the address of the field, or leaving it
+// as nil.
+type fieldAssignments map[*types.Var][]ast.Expr
+
+// annotateSafeFields marks the fields of function type, where every function assigned to the field
+// by this package is safe. This includes the struct literals returned by constructors e.g.
+// `return &server{handler: handle}`. An exported field of a struct in the exported API can be
+// assigned by any package that imports it, so it's never marked. A field can be assigned another
+// field.
+func annotateSafeFields(pass *analysis.Pass) error {
+	assignments := getFieldAssignments(pass)
+	for field := range assignments {
+		if isFieldInExportedAPI(pass, field) {
+			delete(assignments, field)
+		}
+	}
+
+	markUntilFixpoint(assignments, func(field *types.Var, values []ast.Expr) bool {
+		if field.Pkg() != pass.Pkg || !allFuncsSafe(pass, values) {
+			return false
+		}
+
+		pass.ExportObjectFact(field, new(safeFieldFact))
+		return true
+	})
+
+	return nil
+}
+
+// isStructFieldSafe checks if every function assigned to the field is safe. Fields from other packages may
+// also be assigned by this package, so those assignments are checked as well.
+func isStructFieldSafe(pass *analysis.Pass, field *types.Var) bool {
+	field = field.Origin()
+	if !pass.ImportObjectFact(field, &safeFieldFact{}) {
+		return false
+	}
+
+	if field.Pkg() == pass.Pkg {
+		return true
+	}
+
+	return allFuncsSafe(pass, getFieldAssignments(pass)[field])
+}
+
+func allFuncsSafe(pass *analysis.Pass, values []ast.Expr) bool {
+	for _, value := range values {
+		if value == nil || !isFuncSafe(pass, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getFieldAssignments finds every value this package assigns to a field of function type, either
+// through a struct literal or an assignment to the field.
+func getFieldAssignments(pass *analysis.Pass) fieldAssignments {
+	assignments := fieldAssignments{}
+	add := func(field *types.Var, value ast.Expr) {
+		if _, ok := field.Type().Underlying().(*types.Signature); ok {
+			field = field.Origin()
+			assignments[field] = append(assignments[field], value)
+		}
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.CompositeLit:
+				addCompositeLitFields(pass, node, add)
+			case *ast.AssignStmt:
+				for i, lhs := range node.Lhs {
+					field, ok := getSelectedField(pass, lhs)
+					if !ok {
+						continue
+					}
+
+					if len(node.Lhs) == len(node.Rhs) {
+						add(field, node.Rhs[i])
+					} else {
+						add(field, nil)
+					}
+				}
+			case *ast.UnaryExpr:
+				if field, ok := getSelectedField(pass, node.X); ok && node.Op == token.AND {
+					add(field, nil)
+				}
+			case *ast.CallExpr:
+				// new(T) leaves every field as nil.
+				if id, ok := astutil.Unparen(node.Fun).(*ast.Ident); ok && len(node.Args) == 1 {
+					if builtin, ok := pass.TypesInfo.Uses[id].(*types.Builtin); ok && builtin.Name() == "new" {
+						addZeroFields(pass.TypesInfo.TypeOf(node.Args[0]), add)
+					}
+				}
+			case *ast.ValueSpec:
+				// A variable declared without a value leaves every field as nil.
+				if len(node.Values) == 0 && node.Type != nil {
+					addZeroFields(pass.TypesInfo.TypeOf(node.Type), add)
+				}
+			}
+
+			return true
+		})
+	}
+
+	return assignments
+}
+
+// addCompositeLitFields adds the value of each field set by the struct literal, and a nil value for
+// the fields it leaves out.
+func addCompositeLitFields(pass *analysis.Pass, clit *ast.CompositeLit, add func(*types.Var, ast.Expr)) {
+	st, ok := pass.TypesInfo.TypeOf(clit).Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+
+	set := map[*types.Var]bool{}
+	for i, elt := range clit.Elts {
+		var field *types.Var
+		var value ast.Expr
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			key, ok := kv.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+
+			field, _ = pass.TypesInfo.Uses[key].(*types.Var)
+			value = kv.Value
+		} else if i < st.NumFields() {
+			field, value = st.Field(i), elt
+		}
+
+		if field != nil {
+			set[field] = true
+			add(field, value)
+		}
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		if !set[st.Field(i)] {
+			add(st.Field(i), nil)
+		}
+	}
+}
+
+// addZeroFields adds a nil value for each field of the struct type.
+func addZeroFields(ty types.Type, add func(*types.Var, ast.Expr)) {
+	if ty == nil {
+		return
+	}
+
+	st, ok := ty.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		add(st.Field(i), nil)
+	}
+}
+
+// getSelectedField gets the struct field the expression selects e.g. `handler` in `s.handler`.
+func getSelectedField(pass *analysis.Pass, x ast.Expr) (*types.Var, bool) {
+	sel, ok := astutil.Unparen(x).(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.FieldVal {
+		return nil, false
+	}
+
+	field, ok := selection.Obj().(*types.Var)
+	return field, ok
+}
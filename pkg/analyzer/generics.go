@@ -0,0 +1,337 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// typeParamObligation is a Goroutine in a generic function, that is only safe when the function is
+// instantiated with a safe type argument. The Goroutine either calls a method of the type parameter
+// e.g. `go t.safe()`, or calls a parameter whose type is the type parameter e.g. `go f()`.
+type typeParamObligation struct {
+	TypeParam     int
+	TypeParamName string
+	// Method is the method called on the type parameter, it's empty when the Goroutine calls a parameter.
+	Method string
+	// Param is the index of the parameter called by the Goroutine, or -1 when it calls a method.
+	Param     int
+	ParamName string
+}
+
+func (o typeParamObligation) String() string {
+	if o.Method != "" {
+		return fmt.Sprintf("%s.%s", o.TypeParamName, o.Method)
+	}
+
+	return fmt.Sprintf("%s %s", o.ParamName, o.TypeParamName)
+}
+
+// annotateTypeParamObligations marks the generic functions whose Goroutines depend on their type
+// arguments, either directly or by instantiating another generic function with their own type
+// parameters.
+func annotateTypeParamObligations(pass *analysis.Pass) error {
+	obligations := collectUntilFixpoint(pass, func(fdecl *ast.FuncDecl, known map[types.Object][]typeParamObligation) []typeParamObligation {
+		if fdecl.Type.TypeParams == nil {
+			return nil
+		}
+
+		return getTypeParamObligations(pass, fdecl, known)
+	})
+
+	for fn, found := range obligations {
+		pass.ExportObjectFact(fn, &typeParamObligationsFact{Obligations: found})
+	}
+
+	return nil
+}
+
+// getTypeParamObligations finds the obligations of the generic function. known holds the obligations
+// found so far for the generic functions of this package.
+func getTypeParamObligations(pass *analysis.Pass, fdecl *ast.FuncDecl, known map[types.Object][]typeParamObligation) []typeParamObligation {
+	var found []typeParamObligation
+	add := func(o typeParamObligation) {
+		for _, cur := range found {
+			if cur == o {
+				return
+			}
+		}
+
+		found = append(found, o)
+	}
+
+	ast.Inspect(fdecl.Body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.GoStmt:
+			if o, ok := getGoStmtObligation(pass, fdecl, node); ok {
+				add(o)
+			}
+		case *ast.CallExpr:
+			origin, typeArgs, ok := getInstantiation(pass, node)
+			if !ok {
+				return true
+			}
+
+			calleeObligations, ok := known[origin]
+			if !ok {
+				calleeObligations = importTypeParamObligations(pass, origin)
+			}
+
+			for _, o := range calleeObligations {
+				if o, ok := forwardObligation(pass, fdecl, node, typeArgs, o); ok {
+					add(o)
+				}
+			}
+		}
+
+		return true
+	})
+
+	return found
+}
+
+// getGoStmtObligation gets the obligation the Goroutine puts on a type parameter of the function.
+func getGoStmtObligation(pass *analysis.Pass, fdecl *ast.FuncDecl, goStmt *ast.GoStmt) (typeParamObligation, bool) {
+	switch fun := astutil.Unparen(goStmt.Call.Fun).(type) {
+	case *ast.SelectorExpr:
+		sel, ok := pass.TypesInfo.Selections[fun]
+		if !ok || sel.Kind() != types.MethodVal {
+			return typeParamObligation{}, false
+		}
+
+		tp, ok := getOwnTypeParam(pass, fdecl, sel.Recv())
+		if !ok {
+			return typeParamObligation{}, false
+		}
+
+		return typeParamObligation{TypeParam: tp.Index(), TypeParamName: tp.Obj().Name(), Method: fun.Sel.Name, Param: -1}, true
+	case *ast.Ident:
+		return getParamObligation(pass, fdecl, fun)
+	default:
+		return typeParamObligation{}, false
+	}
+}
+
+// getParamObligation gets the obligation for calling the identifier, when it's a parameter of the
+// function whose type is one of its type parameters. A parameter that is reassigned may no longer
+// hold the argument.
+func getParamObligation(pass *analysis.Pass, fdecl *ast.FuncDecl, id *ast.Ident) (typeParamObligation, bool) {
+	v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+	if !ok {
+		return typeParamObligation{}, false
+	}
+
+	tp, ok := getOwnTypeParam(pass, fdecl, v.Type())
+	if !ok || isVarReassigned(pass, fdecl.Body, v) {
+		return typeParamObligation{}, false
+	}
+
+	params := getFuncSignature(pass, fdecl).Params()
+	for i := 0; i < params.Len(); i++ {
+		if params.At(i) == v {
+			return typeParamObligation{TypeParam: tp.Index(), TypeParamName: tp.Obj().Name(), Param: i, ParamName: v.Name()}, true
+		}
+	}
+
+	return typeParamObligation{}, false
+}
+
+// getOwnTypeParam checks if the type is one of the type parameters declared by the function, rather
+// than by an enclosing type.
+func getOwnTypeParam(pass *analysis.Pass, fdecl *ast.FuncDecl, ty types.Type) (*types.TypeParam, bool) {
+	tp, ok := ty.(*types.TypeParam)
+	if !ok {
+		return nil, false
+	}
+
+	typeParams := getFuncSignature(pass, fdecl).TypeParams()
+	if tp.Index() >= typeParams.Len() || typeParams.At(tp.Index()) != tp {
+		return nil, false
+	}
+
+	return tp, true
+}
+
+func getFuncSignature(pass *analysis.Pass, fdecl *ast.FuncDecl) *types.Signature {
+	fn, ok := pass.TypesInfo.Defs[fdecl.Name].(*types.Func)
+	if !ok {
+		return new(types.Signature)
+	}
+
+	sig, _ := fn.Type().(*types.Signature)
+	return sig
+}
+
+// forwardObligation turns the obligation of an instantiated function into an obligation of the
+// enclosing generic function, when the function is instantiated with its own type parameter.
+func forwardObligation(pass *analysis.Pass, fdecl *ast.FuncDecl, call *ast.CallExpr, typeArgs *types.TypeList, o typeParamObligation) (typeParamObligation, bool) {
+	if o.TypeParam >= typeArgs.Len() {
+		return typeParamObligation{}, false
+	}
+
+	tp, ok := getOwnTypeParam(pass, fdecl, typeArgs.At(o.TypeParam))
+	if !ok {
+		return typeParamObligation{}, false
+	}
+
+	if o.Method != "" {
+		return typeParamObligation{TypeParam: tp.Index(), TypeParamName: tp.Obj().Name(), Method: o.Method, Param: -1}, true
+	}
+
+	if o.Param >= len(call.Args) {
+		return typeParamObligation{}, false
+	}
+
+	id, ok := astutil.Unparen(call.Args[o.Param]).(*ast.Ident)
+	if !ok {
+		return typeParamObligation{}, false
+	}
+
+	return getParamObligation(pass, fdecl, id)
+}
+
+// getInstantiation gets the generic function called, and the type arguments it's instantiated with.
+func getInstantiation(pass *analysis.Pass, call *ast.CallExpr) (types.Object, *types.TypeList, bool) {
+	fun := astutil.Unparen(call.Fun)
+	if x := getIDFromIndexParam(fun); x != nil {
+		fun = x
+	}
+
+	var id *ast.Ident
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return nil, nil, false
+	}
+
+	instance, ok := pass.TypesInfo.Instances[id]
+	if !ok {
+		return nil, nil, false
+	}
+
+	callee := typeutil.StaticCallee(pass.TypesInfo, call)
+	if callee == nil {
+		return nil, nil, false
+	}
+
+	origin, _ := getFunctionOrigin(callee)
+
+	return origin, instance.TypeArgs, true
+}
+
+func importTypeParamObligations(pass *analysis.Pass, fn types.Object) []typeParamObligation {
+	fact := new(typeParamObligationsFact)
+	if !pass.ImportObjectFact(fn, fact) {
+		return nil
+	}
+
+	return fact.Obligations
+}
+
+// validateInstantiations checks the type arguments of every generic function instantiated by this
+// package satisfy the obligations of its Goroutines. An obligation passed on to the enclosing
+// function is checked where that function is instantiated instead, as long as every call of it is
+// checked.
+func validateInstantiations(pass *analysis.Pass, df *dataflow, funcDecls map[types.Object]*ast.FuncDecl) error {
+	inspector, ok := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	if !ok {
+		return fmt.Errorf("Expected inspect.Analyzer to be an *inspector.Inspector, but got %T", pass.ResultOf[inspect.Analyzer])
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.CallExpr)(nil), /* Find instantiations */
+	}
+
+	checked := getCheckedFuncs(pass, funcDecls)
+	inspector.WithStack(nodeFilter, func(node ast.Node, push bool, stack []ast.Node) bool {
+		call := node.(*ast.CallExpr)
+		origin, typeArgs, ok := getInstantiation(pass, call)
+		if !push || !ok {
+			return true
+		}
+
+		fdecl := getEnclosingFuncDecl(stack)
+		for _, o := range importTypeParamObligations(pass, origin) {
+			if o.TypeParam >= typeArgs.Len() {
+				continue
+			}
+
+			if fdecl != nil && checked[pass.TypesInfo.Defs[fdecl.Name]] {
+				if _, ok := forwardObligation(pass, fdecl, call, typeArgs, o); ok {
+					continue
+				}
+			}
+
+			if reason := getUnsafeTypeArgReason(pass, df, call, origin, typeArgs.At(o.TypeParam), o); reason != "" {
+				pass.Reportf(call.Pos(), "Goroutine in `%s` should have a defer recover: %s", origin.Name(), reason)
+			}
+		}
+
+		return true
+	})
+
+	return nil
+}
+
+// getEnclosingFuncDecl gets the function declaration the node at the top of the stack is in.
+func getEnclosingFuncDecl(stack []ast.Node) *ast.FuncDecl {
+	for i := len(stack) - 1; i >= 0; i-- {
+		if fdecl, ok := stack[i].(*ast.FuncDecl); ok {
+			return fdecl
+		}
+	}
+
+	return nil
+}
+
+// getUnsafeTypeArgReason explains why the instantiation doesn't satisfy the obligation. It's empty
+// when the obligation is satisfied. A type parameter whose obligation isn't passed on can be
+// instantiated with any type e.g. the type parameters of a receiver, since the methods of a generic
+// type are instantiated with the type rather than where they're called.
+func getUnsafeTypeArgReason(pass *analysis.Pass, df *dataflow, call *ast.CallExpr, origin types.Object, typeArg types.Type, o typeParamObligation) string {
+	if tp, ok := typeArg.(*types.TypeParam); ok && o.Method != "" {
+		return fmt.Sprintf("`%s` is a type parameter, so the types it's instantiated with can't be checked", tp.Obj().Name())
+	}
+
+	if o.Method == "" {
+		if o.Param >= len(call.Args) {
+			return ""
+		}
+
+		return getUnsafeArgReason(pass, df, call, o.Param)
+	}
+
+	impls := []types.Type{typeArg}
+	open := false
+	if iface, ok := typeArg.Underlying().(*types.Interface); ok {
+		impls = getImplementations(pass, iface)
+		open = !isInterfaceClosed(pass, typeArg, o.Method)
+	}
+
+	var unsafe []types.Type
+	for _, ty := range impls {
+		method, _, _ := types.LookupFieldOrMethod(ty, true, origin.Pkg(), o.Method)
+		if method == nil || !isObjectSafe(pass, method) {
+			unsafe = append(unsafe, ty)
+		}
+	}
+
+	if len(unsafe) > 0 {
+		return fmt.Sprintf("`%s` has no recover in %s", o.Method, formatTypes(pass, unsafe))
+	}
+
+	if open {
+		return getOpenInterfaceReason(pass, typeArg)
+	}
+
+	return ""
+}
@@ -0,0 +1,292 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// implicitLaunchers are the functions from the standard library that run a callback on a new
+// Goroutine, keyed by their full name. The value is the index of the callback parameter.
+//
+//nolint:gochecknoglobals
+var implicitLaunchers = map[string]int{
+	"time.AfterFunc":       1,
+	"context.AfterFunc":    1,
+	"runtime.SetFinalizer": 1,
+	"runtime.AddCleanup":   1,
+	"(*sync.WaitGroup).Go": 0,
+}
+
+// annotateLaunchers marks the functions that safely launch a Goroutine with one of their function
+// parameters, like the `Go` and `CtxGo` functions recommended by the README e.g.
+// `func Go(f func()) { go func() { defer handlePanic(); f() }() }`. Functions that pass their parameter
+// on to a launcher are launchers as well e.g. `func (p *pool) Submit(f func()) { Go(f) }`.
+func annotateLaunchers(pass *analysis.Pass) error {
+	launched := collectUntilFixpoint(pass, func(fdecl *ast.FuncDecl, known map[types.Object][]int) []int {
+		return getLaunchedParams(pass, fdecl, known)
+	})
+
+	for fn, params := range launched {
+		pass.ExportObjectFact(fn, &launcherFact{Params: params})
+	}
+
+	return nil
+}
+
+// getLaunchedParams finds the indexes of the parameters the function safely launches in a Goroutine.
+// known holds the parameters found so far for the launchers of this package.
+func getLaunchedParams(pass *analysis.Pass, fdecl *ast.FuncDecl, known map[types.Object][]int) []int {
+	var found []int
+	add := func(i int) {
+		for _, cur := range found {
+			if cur == i {
+				return
+			}
+		}
+
+		found = append(found, i)
+	}
+
+	ast.Inspect(fdecl.Body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.GoStmt:
+			fnLit, ok := astutil.Unparen(node.Call.Fun).(*ast.FuncLit)
+			if !ok || !doesFuncContainRecover(pass, fnLit) || getUnprotectedNode(pass, fnLit) != nil {
+				return true
+			}
+
+			for _, i := range getCalledParams(pass, fdecl, fnLit.Body) {
+				add(i)
+			}
+		case *ast.CallExpr:
+			for _, i := range getForwardedParams(pass, fdecl, node, known) {
+				add(i)
+			}
+		}
+
+		return true
+	})
+
+	return found
+}
+
+// getCalledParams finds the indexes of the function parameters of fdecl that are called by a
+// statement of the body.
+func getCalledParams(pass *analysis.Pass, fdecl *ast.FuncDecl, body *ast.BlockStmt) []int {
+	var called []int
+	for _, stmt := range body.List {
+		exprStmt, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			continue
+		}
+
+		call, ok := astutil.Unparen(exprStmt.X).(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+
+		if i, ok := getSpawnedParam(pass, fdecl, call.Fun); ok {
+			called = append(called, i)
+		}
+	}
+
+	return called
+}
+
+// getForwardedParams finds the indexes of the function parameters of fdecl that are passed on to a
+// launcher by the call.
+func getForwardedParams(pass *analysis.Pass, fdecl *ast.FuncDecl, call *ast.CallExpr, known map[types.Object][]int) []int {
+	callee := typeutil.StaticCallee(pass.TypesInfo, call)
+	if callee == nil {
+		return nil
+	}
+
+	origin, _ := getFunctionOrigin(callee)
+	calleeParams, ok := known[origin]
+	if !ok {
+		calleeParams = importLaunchedParams(pass, origin)
+	}
+
+	var forwarded []int
+	for _, j := range calleeParams {
+		j, ok := getArgIndex(pass, call, j)
+		if !ok {
+			continue
+		}
+
+		if i, ok := getSpawnedParam(pass, fdecl, call.Args[j]); ok {
+			forwarded = append(forwarded, i)
+		}
+	}
+
+	return forwarded
+}
+
+// getLaunchedArgs gets the indexes of the arguments the call passes to a launcher, which runs them
+// on a new Goroutine after it defers a recover.
+func getLaunchedArgs(pass *analysis.Pass, call *ast.CallExpr) []int {
+	callee := typeutil.StaticCallee(pass.TypesInfo, call)
+	if callee == nil {
+		return nil
+	}
+
+	origin, _ := getFunctionOrigin(callee)
+
+	var launched []int
+	for _, i := range importLaunchedParams(pass, origin) {
+		if i, ok := getArgIndex(pass, call, i); ok {
+			launched = append(launched, i)
+		}
+	}
+
+	return launched
+}
+
+func importLaunchedParams(pass *analysis.Pass, fn types.Object) []int {
+	fact := new(launcherFact)
+	if !pass.ImportObjectFact(fn, fact) {
+		return nil
+	}
+
+	return fact.Params
+}
+
+// getLauncherCallbacks gets the indexes of the callbacks the call runs on a new Goroutine, when it
+// calls an implicit launcher e.g. `f` in `time.AfterFunc(d, f)`, or a configured unsafe launcher.
+// Every function argument of an unsafe launcher is treated as a callback.
+func getLauncherCallbacks(pass *analysis.Pass, l launchers, call *ast.CallExpr) []int {
+	callee := typeutil.StaticCallee(pass.TypesInfo, call)
+	if callee == nil {
+		return nil
+	}
+
+	origin, _ := getFunctionOrigin(callee)
+	switch l.getKind(origin) {
+	case safeLauncher, forbiddenLauncher:
+		return nil
+	case unsafeLauncher:
+		var callbacks []int
+		for i, arg := range call.Args {
+			if _, ok := pass.TypesInfo.TypeOf(arg).Underlying().(*types.Signature); ok {
+				callbacks = append(callbacks, i)
+			}
+		}
+
+		return callbacks
+	}
+
+	i, ok := implicitLaunchers[callee.FullName()]
+	if ok {
+		i, ok = getArgIndex(pass, call, i)
+	}
+
+	if !ok || pass.TypesInfo.Types[call.Args[i]].IsNil() {
+		// A nil callback e.g. `runtime.SetFinalizer(v, nil)` clears it rather than running it.
+		return nil
+	}
+
+	return []int{i}
+}
+
+// isCallbackSafe checks if the callback passed as the i-th argument of the call recovers before
+// anything can panic.
+func isCallbackSafe(pass *analysis.Pass, df *dataflow, call *ast.CallExpr, i int, funcDecls map[types.Object]*ast.FuncDecl) bool {
+	if !isFuncSafe(pass, call.Args[i]) && !df.isCallArgSafe(call, i) {
+		return false
+	}
+
+	return getUnprotectedNode(pass, getFuncValueBody(pass, call.Args[i], funcDecls)) == nil
+}
+
+// getFuncValueBody gets the function literal or the declaration of the function in this package, that
+// the expression refers to.
+func getFuncValueBody(pass *analysis.Pass, x ast.Expr, funcDecls map[types.Object]*ast.FuncDecl) ast.Node {
+	x = astutil.Unparen(x)
+	if id := getIDFromIndexParam(x); id != nil {
+		x = id
+	}
+
+	var obj types.Object
+	switch x := x.(type) {
+	case *ast.FuncLit:
+		return x
+	case *ast.Ident:
+		obj = pass.TypesInfo.Uses[x]
+	case *ast.SelectorExpr:
+		obj = pass.TypesInfo.Uses[x.Sel]
+	default:
+		return nil
+	}
+
+	tFn, ok := getFunctionOrigin(obj)
+	if !ok {
+		return nil
+	}
+
+	if fdecl, ok := funcDecls[tFn]; ok {
+		return fdecl
+	}
+
+	return nil
+}
+
+// validateLaunchers checks the callbacks passed to the implicit and the configured launchers, the
+// same way as the function started by a go statement. Calls to forbidden launchers are always
+// reported.
+func validateLaunchers(pass *analysis.Pass, df *dataflow, l launchers, funcDecls map[types.Object]*ast.FuncDecl) error {
+	inspector, ok := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	if !ok {
+		return fmt.Errorf("Expected inspect.Analyzer to be an *inspector.Inspector, but got %T", pass.ResultOf[inspect.Analyzer])
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.CallExpr)(nil), /* Find launchers */
+	}
+
+	inspector.Preorder(nodeFilter, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		if callee := typeutil.StaticCallee(pass.TypesInfo, call); callee != nil {
+			if origin, _ := getFunctionOrigin(callee); l.getKind(origin) == forbiddenLauncher {
+				pass.Reportf(call.Pos(), "Calls to `%s` are forbidden", callee.FullName())
+				return
+			}
+		}
+
+		for _, i := range getLauncherCallbacks(pass, l, call) {
+			validateCallback(pass, call.Args[i], funcDecls, func() bool {
+				return isFuncSafe(pass, call.Args[i]) || df.isCallArgSafe(call, i)
+			})
+		}
+	})
+
+	return nil
+}
+
+// validateCallback reports the callback if it can panic without a recover.
+func validateCallback(pass *analysis.Pass, callback ast.Expr, funcDecls map[types.Object]*ast.FuncDecl, isSafe func() bool) {
+	fn := getFuncValueBody(pass, callback, funcDecls)
+	if unprotected := getUnprotectedNode(pass, fn); unprotected != nil {
+		pass.Reportf(callback.Pos(), "Goroutine can panic before its recover is deferred: `%s`", formatNode(pass, unprotected))
+		return
+	}
+
+	if isSafe() {
+		return
+	}
+
+	if fn != nil {
+		if reason := getIneffectiveRecoverReason(pass, fn); reason != "" {
+			pass.Reportf(callback.Pos(), "Goroutine should have a defer recover: %s", reason)
+			return
+		}
+	}
+
+	pass.Reportf(callback.Pos(), "Goroutine should have a defer recover")
+}
@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// getMethodValue gets the method bound by a method value e.g. `safe` in `s.safe`, or selected by a
+// method expression e.g. `(*myStruct).safe`. Methods of generic types resolve to their origin.
+func getMethodValue(pass *analysis.Pass, x ast.Expr) (types.Object, bool) {
+	sel, ok := astutil.Unparen(x).(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok || (selection.Kind() != types.MethodVal && selection.Kind() != types.MethodExpr) {
+		return nil, false
+	}
+
+	return getFunctionOrigin(selection.Obj())
+}
+
+// isMethodValueVarSafe checks if the local variable only ever holds safe method values e.g.
+// `h := s.safe`. Parameters, range variables and variables whose address is taken can hold anything.
+func isMethodValueVarSafe(pass *analysis.Pass, v *types.Var) bool {
+	if v.Pkg() != pass.Pkg || v.Parent() == v.Pkg().Scope() {
+		return false
+	}
+
+	isVar := func(x ast.Expr) bool {
+		id, ok := astutil.Unparen(x).(*ast.Ident)
+		return ok && pass.TypesInfo.ObjectOf(id) == v
+	}
+
+	defined, safe := false, true
+	isValueSafe := func(value ast.Expr) bool {
+		fn, ok := getMethodValue(pass, value)
+		return ok && isObjectSafe(pass, fn)
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.AssignStmt:
+				for i, lhs := range node.Lhs {
+					if !isVar(lhs) {
+						continue
+					}
+
+					defined = true
+					safe = safe && len(node.Lhs) == len(node.Rhs) && isValueSafe(node.Rhs[i])
+				}
+			case *ast.ValueSpec:
+				for i, name := range node.Names {
+					if pass.TypesInfo.Defs[name] != v {
+						continue
+					}
+
+					defined = true
+					safe = safe && len(node.Names) == len(node.Values) && isValueSafe(node.Values[i])
+				}
+			case *ast.UnaryExpr:
+				safe = safe && !(node.Op == token.AND && isVar(node.X))
+			}
+
+			return safe
+		})
+	}
+
+	return defined && safe
+}
@@ -0,0 +1,356 @@
+package analyzer
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// nonPanickingBuiltins are the builtin functions that can't panic. Closing a channel twice panics,
+// but like sending on a closed channel, we treat it as a bug in the channel's ownership rather than
+// something a recover should handle.
+//
+//nolint:gochecknoglobals
+var nonPanickingBuiltins = map[string]bool{
+	"append":  true,
+	"cap":     true,
+	"close":   true,
+	"complex": true,
+	"copy":    true,
+	"delete":  true,
+	"imag":    true,
+	"len":     true,
+	"new":     true,
+	"print":   true,
+	"println": true,
+	"real":    true,
+	"recover": true,
+}
+
+// noPanicFuncs are the functions from the standard library that are known not to panic, keyed by
+// their full name.
+//
+//nolint:gochecknoglobals
+var noPanicFuncs = map[string]bool{
+	"context.Background":                  true,
+	"context.TODO":                        true,
+	"errors.New":                          true,
+	"strings.Contains":                    true,
+	"strings.EqualFold":                   true,
+	"strings.HasPrefix":                   true,
+	"strings.HasSuffix":                   true,
+	"strings.ToLower":                     true,
+	"strings.ToUpper":                     true,
+	"strings.TrimSpace":                   true,
+	"strconv.Itoa":                        true,
+	"sync/atomic.AddInt32":                true,
+	"sync/atomic.AddInt64":                true,
+	"sync/atomic.AddUint32":               true,
+	"sync/atomic.AddUint64":               true,
+	"sync/atomic.CompareAndSwapInt32":     true,
+	"sync/atomic.CompareAndSwapInt64":     true,
+	"sync/atomic.LoadInt32":               true,
+	"sync/atomic.LoadInt64":               true,
+	"sync/atomic.LoadUint32":              true,
+	"sync/atomic.LoadUint64":              true,
+	"sync/atomic.StoreInt32":              true,
+	"sync/atomic.StoreInt64":              true,
+	"sync/atomic.StoreUint32":             true,
+	"sync/atomic.StoreUint64":             true,
+	"(*sync/atomic.Bool).Load":            true,
+	"(*sync/atomic.Bool).Store":           true,
+	"(*sync/atomic.Int32).Add":            true,
+	"(*sync/atomic.Int32).Load":           true,
+	"(*sync/atomic.Int32).Store":          true,
+	"(*sync/atomic.Int64).Add":            true,
+	"(*sync/atomic.Int64).CompareAndSwap": true,
+	"(*sync/atomic.Int64).Load":           true,
+	"(*sync/atomic.Int64).Store":          true,
+	"(*sync/atomic.Uint64).Add":           true,
+	"(*sync/atomic.Uint64).Load":          true,
+	"(*sync/atomic.Uint64).Store":         true,
+	"(*sync.Mutex).Lock":                  true,
+	"(*sync.RWMutex).Lock":                true,
+	"(*sync.RWMutex).RLock":               true,
+	"time.Now":                            true,
+	"time.Since":                          true,
+	"(time.Time).Sub":                     true,
+	"(time.Duration).String":              true,
+}
+
+// annotateNoPanic marks the functions that can't panic.
+func annotateNoPanic(pass *analysis.Pass) error {
+	markUntilFixpoint(getFuncDecls(pass), func(fn types.Object, fdecl *ast.FuncDecl) bool {
+		if mayPanic(pass, fdecl.Body) {
+			return false
+		}
+
+		pass.ExportObjectFact(fn, new(noPanicFact))
+		return true
+	})
+
+	return nil
+}
+
+// mayPanic checks if running the node could panic. It's conservative, so any call to a function
+// or operation that might fail at runtime is treated as panicking e.g. indexing, map writes, type
+// assertions and dereferences.
+func mayPanic(pass *analysis.Pass, node ast.Node) bool {
+	panics := false
+	// commaOk are the type assertions that report failure through a second value, instead of panicking.
+	commaOk := map[ast.Expr]bool{}
+	ast.Inspect(node, func(node ast.Node) bool {
+		if panics {
+			return false
+		}
+
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			// Creating a closure doesn't run it.
+			return false
+		case *ast.GoStmt:
+			// Only the function value and the arguments are evaluated by a go or defer statement.
+			panics = mayCallOperandsPanic(pass, node.Call)
+			return false
+		case *ast.DeferStmt:
+			// The deferred call still runs before the function returns.
+			panics = mayCallOperandsPanic(pass, node.Call) || mayCallPanic(pass, node.Call)
+			return false
+		case *ast.AssignStmt:
+			if len(node.Lhs) == 2 && len(node.Rhs) == 1 {
+				commaOk[astutil.Unparen(node.Rhs[0])] = true
+			}
+
+			panics = mayAssignPanic(pass, node.Tok, node.Lhs, node.Rhs)
+		case *ast.ValueSpec:
+			if len(node.Names) == 2 && len(node.Values) == 1 {
+				commaOk[astutil.Unparen(node.Values[0])] = true
+			}
+		case *ast.IncDecStmt:
+			panics = isMapIndex(pass, node.X)
+		case *ast.CallExpr:
+			panics = mayCallPanic(pass, node)
+		case *ast.IndexExpr:
+			panics = mayIndexPanic(pass, node.X)
+		case *ast.SliceExpr:
+			panics = true
+		case *ast.TypeAssertExpr:
+			// Type switches use a type assertion without a type, which can't panic.
+			panics = node.Type != nil && !commaOk[node]
+		case *ast.StarExpr:
+			panics = pass.TypesInfo.Types[node].IsValue()
+		case *ast.SelectorExpr:
+			// Selecting a field, or a method with a value receiver, through a pointer dereferences it.
+			sel, ok := pass.TypesInfo.Selections[node]
+			panics = ok && sel.Kind() != types.MethodExpr && sel.Indirect() && !isPointerMethod(sel)
+		case *ast.BinaryExpr:
+			panics = mayDividePanic(pass, node.Op, node.Y) || mayShiftPanic(pass, node.Op, node.Y)
+		}
+
+		return !panics
+	})
+
+	return panics
+}
+
+// mayCallOperandsPanic checks if evaluating the function value or the arguments of a call could
+// panic, without running the call itself.
+func mayCallOperandsPanic(pass *analysis.Pass, call *ast.CallExpr) bool {
+	if _, ok := astutil.Unparen(call.Fun).(*ast.Ident); !ok && mayPanic(pass, call.Fun) {
+		return true
+	}
+
+	for _, arg := range call.Args {
+		if mayPanic(pass, arg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mayAssignPanic checks if an assignment could panic because of the operator or a map write, the
+// operands are inspected separately.
+func mayAssignPanic(pass *analysis.Pass, tok token.Token, lhs, rhs []ast.Expr) bool {
+	for _, x := range lhs {
+		if isMapIndex(pass, x) {
+			return true
+		}
+	}
+
+	if len(rhs) != 1 {
+		return false
+	}
+
+	switch tok {
+	case token.QUO_ASSIGN, token.REM_ASSIGN:
+		return mayDividePanic(pass, token.QUO, rhs[0])
+	case token.SHL_ASSIGN, token.SHR_ASSIGN:
+		return mayShiftPanic(pass, token.SHL, rhs[0])
+	default:
+		return false
+	}
+}
+
+// mayCallPanic checks if the call itself could panic, not counting its operands.
+func mayCallPanic(pass *analysis.Pass, call *ast.CallExpr) bool {
+	fun := astutil.Unparen(call.Fun)
+	if tv, ok := pass.TypesInfo.Types[fun]; ok && tv.IsType() {
+		return mayConvertPanic(pass, tv.Type, call.Args)
+	}
+
+	if fnLit, ok := fun.(*ast.FuncLit); ok {
+		return mayPanic(pass, fnLit.Body)
+	}
+
+	if callee := typeutil.StaticCallee(pass.TypesInfo, call); callee != nil {
+		tFn, _ := getFunctionOrigin(callee)
+		return !noPanicFuncs[callee.FullName()] && !pass.ImportObjectFact(tFn, &noPanicFact{})
+	}
+
+	id, ok := fun.(*ast.Ident)
+	if !ok {
+		return true
+	}
+
+	builtin, ok := pass.TypesInfo.Uses[id].(*types.Builtin)
+	if !ok {
+		return true
+	}
+
+	if builtin.Name() == "make" {
+		// make only panics when it's given a size that is negative or too large.
+		for _, arg := range call.Args[1:] {
+			if pass.TypesInfo.Types[arg].Value == nil {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return !nonPanickingBuiltins[builtin.Name()]
+}
+
+// mayConvertPanic checks if a conversion could panic, which only happens when converting a slice to
+// an array, or an array pointer, that is longer than the slice.
+func mayConvertPanic(pass *analysis.Pass, to types.Type, args []ast.Expr) bool {
+	if len(args) != 1 {
+		return false
+	}
+
+	if _, ok := pass.TypesInfo.TypeOf(args[0]).Underlying().(*types.Slice); !ok {
+		return false
+	}
+
+	switch to := to.Underlying().(type) {
+	case *types.Array:
+		return true
+	case *types.Pointer:
+		_, ok := to.Elem().Underlying().(*types.Array)
+		return ok
+	default:
+		return false
+	}
+}
+
+// mayIndexPanic checks if indexing x could panic. Reading from a map never panics, and an index
+// expression may also be instantiating a generic function or type.
+func mayIndexPanic(pass *analysis.Pass, x ast.Expr) bool {
+	tv, ok := pass.TypesInfo.Types[x]
+	if !ok || tv.IsType() {
+		return false
+	}
+
+	switch tv.Type.Underlying().(type) {
+	case *types.Map, *types.Signature:
+		return false
+	default:
+		return true
+	}
+}
+
+// mayDividePanic checks if dividing by y could panic, which happens for an integer division by zero.
+func mayDividePanic(pass *analysis.Pass, op token.Token, y ast.Expr) bool {
+	if op != token.QUO && op != token.REM {
+		return false
+	}
+
+	tv := pass.TypesInfo.Types[y]
+	if tv.Type == nil {
+		return true
+	}
+
+	if tv.Value != nil {
+		// Division by a constant zero is a compile error.
+		return false
+	}
+
+	basic, ok := tv.Type.Underlying().(*types.Basic)
+	return !ok || basic.Info()&types.IsInteger != 0
+}
+
+// mayShiftPanic checks if a shift could panic, which it does when the count is negative. Only a
+// count of a signed type that isn't constant can be negative at run time.
+func mayShiftPanic(pass *analysis.Pass, op token.Token, y ast.Expr) bool {
+	if op != token.SHL && op != token.SHR {
+		return false
+	}
+
+	tv := pass.TypesInfo.Types[y]
+	if tv.Type == nil {
+		return true
+	}
+
+	if tv.Value != nil {
+		// Shifting by a negative constant is a compile error.
+		return false
+	}
+
+	basic, ok := tv.Type.Underlying().(*types.Basic)
+	return !ok || basic.Info()&types.IsUnsigned == 0
+}
+
+// isPointerMethod checks if the selection is a method with a pointer receiver, on a value that isn't
+// embedded. Selections reports these as indirect, even though the pointer is passed as it is.
+func isPointerMethod(sel *types.Selection) bool {
+	if sel.Kind() != types.MethodVal || len(sel.Index()) != 1 {
+		return false
+	}
+
+	sig, ok := sel.Obj().Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return false
+	}
+
+	_, ok = sig.Recv().Type().(*types.Pointer)
+	return ok
+}
+
+func isMapIndex(pass *analysis.Pass, x ast.Expr) bool {
+	index, ok := astutil.Unparen(x).(*ast.IndexExpr)
+	if !ok {
+		return false
+	}
+
+	_, ok = pass.TypesInfo.TypeOf(index.X).Underlying().(*types.Map)
+	return ok
+}
+
+// formatNode formats the first line of a node, so it can be named in a diagnostic.
+func formatNode(pass *analysis.Pass, node ast.Node) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, pass.Fset, node); err != nil {
+		return ""
+	}
+
+	line, _, _ := strings.Cut(buf.String(), "\n")
+	return strings.TrimSpace(line)
+}
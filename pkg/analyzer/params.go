@@ -0,0 +1,331 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// annotateSpawnedParams marks the functions that start a Goroutine with one of their function
+// parameters e.g. `func spawn(f func()) { go f() }`, either directly or by passing the parameter on to
+// another function that does. The Goroutine is only as safe as the argument, so it's checked where
+// the function is called.
+func annotateSpawnedParams(pass *analysis.Pass) error {
+	spawned := collectUntilFixpoint(pass, func(fdecl *ast.FuncDecl, known map[types.Object][]int) []int {
+		return getSpawnedParams(pass, fdecl, known)
+	})
+
+	for fn, params := range spawned {
+		pass.ExportObjectFact(fn, &spawnsParamFact{Params: params})
+	}
+
+	return nil
+}
+
+// getSpawnedParams finds the indexes of the parameters the function starts a Goroutine with. known
+// holds the parameters found so far for the functions of this package.
+func getSpawnedParams(pass *analysis.Pass, fdecl *ast.FuncDecl, known map[types.Object][]int) []int {
+	var found []int
+	add := func(i int) {
+		for _, cur := range found {
+			if cur == i {
+				return
+			}
+		}
+
+		found = append(found, i)
+	}
+
+	ast.Inspect(fdecl.Body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.GoStmt:
+			if i, ok := getSpawnedParam(pass, fdecl, node.Call.Fun); ok {
+				add(i)
+			}
+		case *ast.CallExpr:
+			callee := typeutil.StaticCallee(pass.TypesInfo, node)
+			if callee == nil {
+				return true
+			}
+
+			origin, _ := getFunctionOrigin(callee)
+			calleeParams, ok := known[origin]
+			if !ok {
+				calleeParams = importSpawnedParams(pass, origin)
+			}
+
+			for _, j := range calleeParams {
+				j, ok := getArgIndex(pass, node, j)
+				if !ok {
+					continue
+				}
+
+				if i, ok := getSpawnedParam(pass, fdecl, node.Args[j]); ok {
+					add(i)
+				}
+			}
+		}
+
+		return true
+	})
+
+	return found
+}
+
+// getSpawnedParam gets the index of the function parameter the expression refers to. Parameters
+// whose type is a type parameter are checked against the type arguments instead, and parameters
+// that are reassigned may no longer hold the argument.
+func getSpawnedParam(pass *analysis.Pass, fdecl *ast.FuncDecl, x ast.Expr) (int, bool) {
+	id, ok := astutil.Unparen(x).(*ast.Ident)
+	if !ok {
+		return 0, false
+	}
+
+	v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+	if !ok {
+		return 0, false
+	}
+
+	if _, ok := v.Type().(*types.TypeParam); ok {
+		return 0, false
+	}
+
+	if _, ok := v.Type().Underlying().(*types.Signature); !ok || isVarReassigned(pass, fdecl.Body, v) {
+		return 0, false
+	}
+
+	params := getFuncSignature(pass, fdecl).Params()
+	for i := 0; i < params.Len(); i++ {
+		if params.At(i) == v {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// isVarReassigned checks if the variable is assigned to, or has its address taken, in the body.
+func isVarReassigned(pass *analysis.Pass, body *ast.BlockStmt, v *types.Var) bool {
+	isVar := func(x ast.Expr) bool {
+		id, ok := astutil.Unparen(x).(*ast.Ident)
+		return ok && pass.TypesInfo.Uses[id] == v
+	}
+
+	reassigned := false
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				reassigned = reassigned || isVar(lhs)
+			}
+		case *ast.UnaryExpr:
+			reassigned = reassigned || (node.Op == token.AND && isVar(node.X))
+		}
+
+		return !reassigned
+	})
+
+	return reassigned
+}
+
+func importSpawnedParams(pass *analysis.Pass, fn types.Object) []int {
+	fact := new(spawnsParamFact)
+	if !pass.ImportObjectFact(fn, fact) {
+		return nil
+	}
+
+	return fact.Params
+}
+
+// getObligatedGoStmts gets the Goroutines that are checked where their function is called or
+// instantiated, rather than where they're started.
+func getObligatedGoStmts(pass *analysis.Pass, funcDecls map[types.Object]*ast.FuncDecl) map[*ast.GoStmt]bool {
+	checked := getCheckedFuncs(pass, funcDecls)
+	goStmts := map[*ast.GoStmt]bool{}
+	for fn, fdecl := range funcDecls {
+		ast.Inspect(fdecl.Body, func(node ast.Node) bool {
+			goStmt, ok := node.(*ast.GoStmt)
+			if !ok {
+				return true
+			}
+
+			if _, ok := getSpawnedParam(pass, fdecl, goStmt.Call.Fun); ok && checked[fn] {
+				goStmts[goStmt] = true
+			} else if _, ok := getGoStmtObligation(pass, fdecl, goStmt); ok && checked[fn] {
+				goStmts[goStmt] = true
+			}
+
+			return true
+		})
+	}
+
+	return goStmts
+}
+
+// getCheckedFuncs gets the functions of this package where every call is a static call we check.
+// An exported function can be called through a value in another package, and a function that is
+// taken as a value or can satisfy an interface can be called without us seeing its arguments.
+func getCheckedFuncs(pass *analysis.Pass, funcDecls map[types.Object]*ast.FuncDecl) map[types.Object]bool {
+	ifaceMethods := getInterfaceMethodNames(pass)
+	checked := map[types.Object]bool{}
+	for fn := range funcDecls {
+		sig, _ := fn.Type().(*types.Signature)
+		isMethod := sig != nil && sig.Recv() != nil
+		checked[fn] = !fn.Exported() && !(isMethod && ifaceMethods[fn.Name()])
+	}
+
+	called := getCalledIdents(pass)
+	for id, obj := range pass.TypesInfo.Uses {
+		if origin, ok := getFunctionOrigin(obj); ok && !called[id] {
+			checked[origin] = false
+		}
+	}
+
+	return checked
+}
+
+// getCalledIdents gets the identifiers of the functions and methods that are called directly e.g.
+// `spawn` in `spawn(f)` or `start` in `s.start[T](f)`.
+func getCalledIdents(pass *analysis.Pass) map[*ast.Ident]bool {
+	called := map[*ast.Ident]bool{}
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			fun := astutil.Unparen(call.Fun)
+			switch x := fun.(type) {
+			case *ast.IndexExpr:
+				fun = x.X
+			case *ast.IndexListExpr:
+				fun = x.X
+			}
+
+			switch fun := astutil.Unparen(fun).(type) {
+			case *ast.Ident:
+				called[fun] = true
+			case *ast.SelectorExpr:
+				called[fun.Sel] = true
+			}
+
+			return true
+		})
+	}
+
+	return called
+}
+
+// getInterfaceMethodNames gets the names of the methods of the interfaces used in this package. A
+// method with an unexported name can only satisfy the interfaces of the package it's declared in.
+func getInterfaceMethodNames(pass *analysis.Pass) map[string]bool {
+	names := map[string]bool{}
+	for _, tv := range pass.TypesInfo.Types {
+		iface, ok := tv.Type.Underlying().(*types.Interface)
+		if !ok {
+			continue
+		}
+
+		for i := 0; i < iface.NumMethods(); i++ {
+			names[iface.Method(i).Name()] = true
+		}
+	}
+
+	return names
+}
+
+// getSpawnedParamVars gets the parameters of the functions in this package that are started in a
+// Goroutine. Passing one on to another spawner is checked where the outer function is called, as long
+// as every call of the outer function is checked.
+func getSpawnedParamVars(pass *analysis.Pass, funcDecls map[types.Object]*ast.FuncDecl) map[types.Object]bool {
+	checked := getCheckedFuncs(pass, funcDecls)
+	vars := map[types.Object]bool{}
+	for fn, fdecl := range funcDecls {
+		if !checked[fn] {
+			continue
+		}
+
+		params := getFuncSignature(pass, fdecl).Params()
+		for _, i := range importSpawnedParams(pass, fn) {
+			vars[params.At(i)] = true
+		}
+	}
+
+	return vars
+}
+
+// validateSpawnedArgs checks the arguments passed to every function that starts a Goroutine with one
+// of its parameters.
+func validateSpawnedArgs(pass *analysis.Pass, df *dataflow, l launchers, funcDecls map[types.Object]*ast.FuncDecl) error {
+	inspector, ok := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	if !ok {
+		return fmt.Errorf("Expected inspect.Analyzer to be an *inspector.Inspector, but got %T", pass.ResultOf[inspect.Analyzer])
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.CallExpr)(nil), /* Find calls to spawners */
+	}
+
+	forwarded := getSpawnedParamVars(pass, funcDecls)
+	inspector.Preorder(nodeFilter, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		callee := typeutil.StaticCallee(pass.TypesInfo, call)
+		if callee == nil {
+			return
+		}
+
+		origin, _ := getFunctionOrigin(callee)
+		if l.getKind(origin) != notLauncher {
+			// The configured launchers are checked by validateLaunchers.
+			return
+		}
+
+		for _, i := range importSpawnedParams(pass, origin) {
+			i, ok := getArgIndex(pass, call, i)
+			if !ok {
+				continue
+			}
+
+			if id, ok := astutil.Unparen(call.Args[i]).(*ast.Ident); ok && forwarded[pass.TypesInfo.Uses[id]] {
+				continue
+			}
+
+			if reason := getUnsafeArgReason(pass, df, call, i); reason != "" {
+				pass.Reportf(call.Pos(), "Goroutine in `%s` should have a defer recover: %s", origin.Name(), reason)
+			}
+		}
+	})
+
+	return nil
+}
+
+// getArgIndex gets the index of the argument the call passes as the i-th parameter of the callee. A
+// method expression e.g. `(*T).spawn(t, f)` passes the receiver as the first argument.
+func getArgIndex(pass *analysis.Pass, call *ast.CallExpr, i int) (int, bool) {
+	if sel, ok := astutil.Unparen(call.Fun).(*ast.SelectorExpr); ok {
+		if selection, ok := pass.TypesInfo.Selections[sel]; ok && selection.Kind() == types.MethodExpr {
+			i++
+		}
+	}
+
+	return i, i < len(call.Args)
+}
+
+// getUnsafeArgReason explains why the i-th argument of the call isn't safe to run in a Goroutine. It's
+// empty when the argument is safe.
+func getUnsafeArgReason(pass *analysis.Pass, df *dataflow, call *ast.CallExpr, i int) string {
+	arg := call.Args[i]
+	if isFuncSafe(pass, arg) || df.isCallArgSafe(call, i) {
+		return ""
+	}
+
+	return fmt.Sprintf("`%s` has no recover", formatNode(pass, arg))
+}
@@ -0,0 +1,147 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/ctrlflow"
+	"golang.org/x/tools/go/cfg"
+)
+
+// recoverPaths tracks where a recovering defer is registered along the paths through a function.
+type recoverPaths struct {
+	g *cfg.CFG
+	// recovers are the live blocks that register a recovering defer.
+	recovers map[*cfg.Block]bool
+	// protected are the blocks that have registered a recovering defer by the time they end, no
+	// matter which path was taken to reach them.
+	protected map[*cfg.Block]bool
+	preds     map[*cfg.Block][]*cfg.Block
+	isRecover func(*ast.DeferStmt) bool
+}
+
+// newRecoverPaths builds the recover paths of a function, fn must be either an *ast.FuncDecl or an
+// *ast.FuncLit. It returns nil if the function has no control flow graph.
+func newRecoverPaths(pass *analysis.Pass, fn ast.Node) *recoverPaths {
+	g := getFuncCFG(pass, fn)
+	if g == nil {
+		return nil
+	}
+
+	paths := &recoverPaths{
+		g:         g,
+		recovers:  map[*cfg.Block]bool{},
+		protected: map[*cfg.Block]bool{},
+		preds:     map[*cfg.Block][]*cfg.Block{},
+		isRecover: func(deferStmt *ast.DeferStmt) bool {
+			return getDeferRecoverUse(pass, deferStmt.Call) == recoverCalled
+		},
+	}
+
+	for _, block := range g.Blocks {
+		for _, succ := range block.Succs {
+			paths.preds[succ] = append(paths.preds[succ], block)
+		}
+
+		for _, node := range block.Nodes {
+			deferStmt, ok := node.(*ast.DeferStmt)
+			if ok && block.Live && paths.isRecover(deferStmt) {
+				paths.recovers[block] = true
+			}
+		}
+	}
+
+	// Start by assuming every block is protected, and remove the ones that are reachable through an
+	// unprotected path until nothing changes.
+	for _, block := range g.Blocks {
+		paths.protected[block] = block.Live
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, block := range g.Blocks {
+			if paths.protected[block] && !paths.recovers[block] && !paths.isProtectedOnEntry(block) {
+				paths.protected[block] = false
+				changed = true
+			}
+		}
+	}
+
+	return paths
+}
+
+// isProtectedOnEntry checks if every path reaching the block has registered a recovering defer.
+func (p *recoverPaths) isProtectedOnEntry(block *cfg.Block) bool {
+	if block == p.g.Blocks[0] {
+		// The entry block starts unprotected.
+		return false
+	}
+
+	for _, pred := range p.preds[block] {
+		if pred.Live && !p.protected[pred] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getCoverage checks if a recovering defer is registered on every path, by the time the function
+// either returns or panics.
+func (p *recoverPaths) getCoverage() recoverCoverage {
+	if len(p.recovers) == 0 {
+		return recoverOnNoPath
+	}
+
+	for _, block := range p.g.Blocks {
+		if block.Live && len(block.Succs) == 0 && !p.protected[block] {
+			return recoverOnSomePaths
+		}
+	}
+
+	return recoverOnEveryPath
+}
+
+// getFirstUnprotectedNode finds the earliest node matching the predicate that may run before a
+// recovering defer is registered.
+func (p *recoverPaths) getFirstUnprotectedNode(match func(ast.Node) bool) ast.Node {
+	var first ast.Node
+	for _, block := range p.g.Blocks {
+		if !block.Live || p.isProtectedOnEntry(block) {
+			continue
+		}
+
+		for _, node := range block.Nodes {
+			if deferStmt, ok := node.(*ast.DeferStmt); ok && p.isRecover(deferStmt) {
+				break
+			}
+
+			if match(node) && (first == nil || node.Pos() < first.Pos()) {
+				first = node
+			}
+		}
+	}
+
+	return first
+}
+
+// getFuncCFG gets the control flow graph of a function, fn must be either an *ast.FuncDecl or an
+// *ast.FuncLit.
+func getFuncCFG(pass *analysis.Pass, fn ast.Node) *cfg.CFG {
+	cfgs, ok := pass.ResultOf[ctrlflow.Analyzer].(*ctrlflow.CFGs)
+	if !ok {
+		fmt.Printf("Expected ctrlflow.Analyzer to be an *ctrlflow.CFGs, but got %T\n", pass.ResultOf[ctrlflow.Analyzer])
+		return nil
+	}
+
+	switch fn := fn.(type) {
+	case *ast.FuncDecl:
+		return cfgs.FuncDecl(fn)
+	case *ast.FuncLit:
+		return cfgs.FuncLit(fn)
+	default:
+		fmt.Printf("[getFuncCFG] Not handling type: %T\n", fn)
+		return nil
+	}
+}
@@ -0,0 +1,290 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// recoverUse describes how a function uses recover. A recover only stops a panic when it's called
+// directly by the deferred function, see https://go.dev/ref/spec#Handling_panics.
+type recoverUse int
+
+const (
+	noRecover recoverUse = iota
+	// recoverDeferred is a recover that is deferred itself, so it's referenced but never called by the
+	// deferred function.
+	recoverDeferred
+	// recoverShadowed is an identifier named recover that isn't the builtin.
+	recoverShadowed
+	// recoverNested is a recover called by a nested function literal, which always returns nil.
+	recoverNested
+	// recoverIndirect is a recover handler called by the deferred function, rather than deferred
+	// itself e.g. `defer func() { handlePanic() }()`, so recover is one frame too deep.
+	recoverIndirect
+	// recoverRepanics is a recover that stops the panic, but the recovering function always panics
+	// again or exits the process.
+	recoverRepanics
+	// recoverCalled is a recover that stops the panic.
+	recoverCalled
+)
+
+// ineffectiveReason explains why the recover doesn't stop a panic.
+func (u recoverUse) ineffectiveReason() string {
+	switch u {
+	case recoverDeferred:
+		return "recover is deferred directly, so it is never called by the deferred function"
+	case recoverShadowed:
+		return "recover is shadowed, so it does not refer to the builtin"
+	case recoverNested:
+		return "recover is called by a nested function literal, so it always returns nil"
+	case recoverIndirect:
+		return "recover handler is called by the deferred function instead of being deferred, so its recover always returns nil"
+	default:
+		return ""
+	}
+}
+
+// annotateRecoverHandlers marks the functions that call recover themselves, so deferring them
+// recovers from a panic e.g. `defer handlePanic()`.
+func annotateRecoverHandlers(pass *analysis.Pass) error {
+	inspector, ok := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	if !ok {
+		return fmt.Errorf("Expected inspect.Analyzer to be an *inspector.Inspector, but got %T", pass.ResultOf[inspect.Analyzer])
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.FuncDecl)(nil), /* Find Function */
+	}
+
+	inspector.Preorder(nodeFilter, func(node ast.Node) {
+		fdecl, ok := node.(*ast.FuncDecl)
+		if !ok || fdecl.Body == nil {
+			return
+		}
+
+		if getRecoverUse(pass, fdecl.Body) != recoverCalled || getRepanic(pass, fdecl) != nil {
+			return
+		}
+
+		fn := pass.TypesInfo.ObjectOf(fdecl.Name)
+		if fn == nil {
+			// Type information may be incomplete.
+			return
+		}
+
+		pass.ExportObjectFact(fn, new(isRecoverHandlerFact))
+	})
+
+	return nil
+}
+
+// getRecoverUse finds the most effective use of recover in the body of a function. Function literals
+// nested in the body run in their own frame, so calling recover there has no effect.
+func getRecoverUse(pass *analysis.Pass, body *ast.BlockStmt) recoverUse {
+	use := noRecover
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			if getRecoverUse(pass, node.Body) == recoverCalled && use < recoverNested {
+				use = recoverNested
+			}
+
+			return false
+		case *ast.CallExpr:
+			id, ok := astutil.Unparen(node.Fun).(*ast.Ident)
+			if ok && isBuiltinRecover(pass, id) {
+				use = recoverCalled
+			} else if use < recoverIndirect && isRecoverHandler(pass, node.Fun) {
+				use = recoverIndirect
+			}
+		case *ast.Ident:
+			if node.Name == "recover" && !isBuiltinRecover(pass, node) && use < recoverShadowed {
+				use = recoverShadowed
+			}
+		}
+
+		return use != recoverCalled
+	})
+
+	return use
+}
+
+// isBuiltinRecover checks if the identifier refers to the builtin recover, rather than a variable or
+// function that shadows it.
+func isBuiltinRecover(pass *analysis.Pass, id *ast.Ident) bool {
+	obj := pass.TypesInfo.Uses[id]
+
+	return obj != nil && obj == types.Universe.Lookup("recover")
+}
+
+// isRecoverHandler checks if the deferred function is a function or method that recovers, e.g.
+// `defer handlePanic()`, `defer logger.RecoverPanic()` or `defer h.recover()`.
+func isRecoverHandler(pass *analysis.Pass, fun ast.Expr) bool {
+	tFn, ok := getDeferredFunc(pass, fun)
+	if !ok {
+		return false
+	}
+
+	return pass.ImportObjectFact(tFn, &isRecoverHandlerFact{})
+}
+
+// getDeferredFunc gets the function or method the deferred call refers to.
+func getDeferredFunc(pass *analysis.Pass, fun ast.Expr) (types.Object, bool) {
+	var tFn types.Object
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		tFn = pass.TypesInfo.ObjectOf(fun)
+	case *ast.SelectorExpr:
+		// Selections covers method values and method expressions, otherwise it's a qualified identifier.
+		if sel, ok := pass.TypesInfo.Selections[fun]; ok {
+			tFn = sel.Obj()
+		} else {
+			tFn = pass.TypesInfo.ObjectOf(fun.Sel)
+		}
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		return getDeferredFunc(pass, getIDFromIndexParam(fun))
+	case *ast.ParenExpr:
+		return getDeferredFunc(pass, fun.X)
+	}
+
+	return getFunctionOrigin(tFn)
+}
+
+// getHandlerDecl gets the declaration of the deferred function, when it's declared in this package.
+func getHandlerDecl(pass *analysis.Pass, fun ast.Expr) *ast.FuncDecl {
+	tFn, ok := getDeferredFunc(pass, fun)
+	if !ok || tFn.Pkg() != pass.Pkg {
+		return nil
+	}
+
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			fdecl, ok := decl.(*ast.FuncDecl)
+			if ok && fdecl.Body != nil && pass.TypesInfo.Defs[fdecl.Name] == tFn {
+				return fdecl
+			}
+		}
+	}
+
+	return nil
+}
+
+// getDeferRecoverUse checks how the deferred call uses recover.
+func getDeferRecoverUse(pass *analysis.Pass, call *ast.CallExpr) recoverUse {
+	switch fun := astutil.Unparen(call.Fun).(type) {
+	case *ast.FuncLit:
+		use := getRecoverUse(pass, fun.Body)
+		if use == recoverCalled && getRepanic(pass, fun) != nil {
+			return recoverRepanics
+		}
+
+		return use
+	case *ast.Ident:
+		if isBuiltinRecover(pass, fun) {
+			// `defer recover()` makes recover the deferred function, so it isn't called by one.
+			return recoverDeferred
+		}
+	}
+
+	if isRecoverHandler(pass, call.Fun) {
+		return recoverCalled
+	}
+
+	// A handler that always panics again doesn't get the isRecoverHandlerFact e.g. `defer rethrowPanic()`.
+	if fdecl := getHandlerDecl(pass, call.Fun); fdecl != nil && getRecoverUse(pass, fdecl.Body) == recoverCalled && getRepanic(pass, fdecl) != nil {
+		return recoverRepanics
+	}
+
+	return noRecover
+}
+
+// getRepanickingHandler gets the function literal or the declaration of the handler that the deferred
+// call runs, when it always panics again after it recovers.
+func getRepanickingHandler(pass *analysis.Pass, call *ast.CallExpr) ast.Node {
+	if fnLit, ok := astutil.Unparen(call.Fun).(*ast.FuncLit); ok {
+		return fnLit
+	}
+
+	if fdecl := getHandlerDecl(pass, call.Fun); fdecl != nil {
+		return fdecl
+	}
+
+	return nil
+}
+
+// recoverCoverage describes which paths through a function register a recovering defer.
+type recoverCoverage int
+
+const (
+	recoverOnNoPath recoverCoverage = iota
+	recoverOnSomePaths
+	recoverOnEveryPath
+)
+
+// doesFuncContainRecover checks if function has a recover, that is deferred on every path through it.
+// fn must be either an *ast.FuncDecl or an *ast.FuncLit.
+func doesFuncContainRecover(pass *analysis.Pass, fn ast.Node) bool {
+	return getRecoverCoverage(pass, fn) == recoverOnEveryPath
+}
+
+// getRecoverCoverage walks the control flow graph of the function to check if a recovering defer is
+// registered on every path, by the time the function either returns or panics.
+func getRecoverCoverage(pass *analysis.Pass, fn ast.Node) recoverCoverage {
+	paths := newRecoverPaths(pass, fn)
+	if paths == nil {
+		return recoverOnNoPath
+	}
+
+	return paths.getCoverage()
+}
+
+// getIneffectiveRecoverReason explains why the recovers deferred by the function don't stop a panic.
+// It's empty if the function never tried to recover. fn must be either an *ast.FuncDecl or an
+// *ast.FuncLit.
+func getIneffectiveRecoverReason(pass *analysis.Pass, fn ast.Node) string {
+	if getRecoverCoverage(pass, fn) == recoverOnSomePaths {
+		return "recover is only deferred on some paths"
+	}
+
+	use := noRecover
+	var deferred *ast.CallExpr
+	ast.Inspect(getFuncBody(fn), func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.DeferStmt:
+			if deferUse := getDeferRecoverUse(pass, node.Call); deferUse > use {
+				use = deferUse
+				deferred = node.Call
+			}
+
+			return false
+		}
+
+		return true
+	})
+
+	if use == recoverRepanics {
+		return fmt.Sprintf("recover is always followed by `%s`, so the panic still crashes the program", formatNode(pass, getRepanic(pass, getRepanickingHandler(pass, deferred))))
+	}
+
+	return use.ineffectiveReason()
+}
+
+// getFuncBody gets the body of a function declaration or literal.
+func getFuncBody(fn ast.Node) *ast.BlockStmt {
+	switch fn := fn.(type) {
+	case *ast.FuncDecl:
+		return fn.Body
+	case *ast.FuncLit:
+		return fn.Body
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,269 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/cfg"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// panickingFuncs are the functions that always panic, keyed by their full name. The ones that exit
+// the process are in terminatingFuncs.
+//
+//nolint:gochecknoglobals
+var panickingFuncs = map[string]bool{
+	"log.Panic":             true,
+	"log.Panicf":            true,
+	"log.Panicln":           true,
+	"(*log.Logger).Panic":   true,
+	"(*log.Logger).Panicf":  true,
+	"(*log.Logger).Panicln": true,
+}
+
+// annotateAlwaysPanics marks the functions that panic or exit the process on every path. A function
+// that defers a recover returns normally after the panic, so it's never marked.
+func annotateAlwaysPanics(pass *analysis.Pass) error {
+	markUntilFixpoint(getFuncDecls(pass), func(fn types.Object, fdecl *ast.FuncDecl) bool {
+		if doesDeferRecover(pass, fdecl.Body) {
+			return false
+		}
+
+		g := getFuncCFG(pass, fdecl)
+		if g == nil || getTerminatingNode(pass, g.Blocks[0], 0, nil) == nil {
+			return false
+		}
+
+		pass.ExportObjectFact(fn, new(alwaysPanicsFact))
+		return true
+	})
+
+	return nil
+}
+
+// doesDeferRecover checks if the body defers a call that stops a panic. The handlers of this package
+// don't have their facts yet, so any handler of this package that calls recover counts.
+func doesDeferRecover(pass *analysis.Pass, body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.DeferStmt:
+			switch getDeferRecoverUse(pass, node.Call) {
+			case recoverCalled:
+				found = true
+			case noRecover:
+				fdecl := getHandlerDecl(pass, node.Call.Fun)
+				found = fdecl != nil && getRecoverUse(pass, fdecl.Body) == recoverCalled
+			}
+		}
+
+		return !found
+	})
+
+	return found
+}
+
+// getRepanic finds the call that panics again, or exits the process, on every path after the function
+// recovers. It's nil if the recovered panic can be handled, fn must be either an *ast.FuncDecl or an
+// *ast.FuncLit.
+func getRepanic(pass *analysis.Pass, fn ast.Node) ast.Node {
+	g := getFuncCFG(pass, fn)
+	if g == nil {
+		return nil
+	}
+
+	for _, block := range g.Blocks {
+		if !block.Live {
+			continue
+		}
+
+		for i, node := range block.Nodes {
+			if doesNodeCallRecover(pass, node) {
+				return getTerminatingNode(pass, block, i+1, getRecoveredVar(pass, node))
+			}
+		}
+	}
+
+	return nil
+}
+
+// getTerminatingNode finds the earliest call that panics or exits the process, when every path
+// starting at the given node of the block reaches one. It's nil if some path can finish normally, or
+// can loop without reaching one. When recovered is set, we follow the recovered value and skip the
+// branches where it's nil, since there was no panic to handle.
+func getTerminatingNode(pass *analysis.Pass, block *cfg.Block, start int, recovered types.Object) ast.Node {
+	var first ast.Node
+	// done holds whether the blocks we finished walking terminate, and onPath the blocks of the
+	// current path.
+	done := map[*cfg.Block]bool{}
+	onPath := map[*cfg.Block]bool{}
+
+	var walk func(block *cfg.Block, start int) bool
+	walk = func(block *cfg.Block, start int) (terminates bool) {
+		if start == 0 {
+			if onPath[block] {
+				// A loop that doesn't reach a terminating call can keep running instead.
+				return false
+			}
+
+			if terminates, ok := done[block]; ok {
+				return terminates
+			}
+
+			onPath[block] = true
+			defer func() {
+				delete(onPath, block)
+				done[block] = terminates
+			}()
+		}
+
+		for _, node := range block.Nodes[start:] {
+			if isTerminatingNode(pass, node) {
+				if first == nil || node.Pos() < first.Pos() {
+					first = node
+				}
+
+				return true
+			}
+		}
+
+		succs := getFeasibleSuccs(pass, block, recovered)
+		if len(succs) == 0 {
+			return false
+		}
+
+		for _, succ := range succs {
+			if !walk(succ, 0) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if !walk(block, start) {
+		return nil
+	}
+
+	return first
+}
+
+// getFeasibleSuccs gets the successors of the block, skipping the branch where the recovered value is
+// nil.
+func getFeasibleSuccs(pass *analysis.Pass, block *cfg.Block, recovered types.Object) []*cfg.Block {
+	if recovered == nil || len(block.Succs) != 2 || len(block.Nodes) == 0 {
+		return block.Succs
+	}
+
+	cond, ok := block.Nodes[len(block.Nodes)-1].(*ast.BinaryExpr)
+	if !ok || !isNilComparison(pass, cond, recovered) {
+		return block.Succs
+	}
+
+	// The first successor is taken when the condition is true.
+	if cond.Op == token.NEQ {
+		return block.Succs[:1]
+	}
+
+	return block.Succs[1:]
+}
+
+// isNilComparison checks if the expression compares the variable against nil.
+func isNilComparison(pass *analysis.Pass, cond *ast.BinaryExpr, v types.Object) bool {
+	if cond.Op != token.EQL && cond.Op != token.NEQ {
+		return false
+	}
+
+	isVar := func(x ast.Expr) bool {
+		id, ok := astutil.Unparen(x).(*ast.Ident)
+		return ok && pass.TypesInfo.Uses[id] == v
+	}
+
+	isNil := func(x ast.Expr) bool {
+		return pass.TypesInfo.Types[x].IsNil()
+	}
+
+	return (isVar(cond.X) && isNil(cond.Y)) || (isNil(cond.X) && isVar(cond.Y))
+}
+
+// isTerminatingNode checks if the node is a call statement that panics or exits the process.
+func isTerminatingNode(pass *analysis.Pass, node ast.Node) bool {
+	exprStmt, ok := node.(*ast.ExprStmt)
+	if !ok {
+		return false
+	}
+
+	call, ok := astutil.Unparen(exprStmt.X).(*ast.CallExpr)
+	return ok && isTerminatingCall(pass, call)
+}
+
+// isTerminatingCall checks if the call always panics or exits the process, e.g. `panic(r)`,
+// `log.Fatal(r)`, `os.Exit(1)` or a call to a function with the alwaysPanicsFact.
+func isTerminatingCall(pass *analysis.Pass, call *ast.CallExpr) bool {
+	if id, ok := astutil.Unparen(call.Fun).(*ast.Ident); ok {
+		if builtin, ok := pass.TypesInfo.Uses[id].(*types.Builtin); ok {
+			return builtin.Name() == "panic"
+		}
+	}
+
+	callee := typeutil.StaticCallee(pass.TypesInfo, call)
+	if callee == nil {
+		return false
+	}
+
+	if panickingFuncs[callee.FullName()] || isProcessTerminatingFunc(callee) {
+		return true
+	}
+
+	tFn, _ := getFunctionOrigin(callee)
+	return pass.ImportObjectFact(tFn, &alwaysPanicsFact{})
+}
+
+// doesNodeCallRecover checks if the node calls the builtin recover, outside of nested function literals.
+func doesNodeCallRecover(pass *analysis.Pass, node ast.Node) bool {
+	found := false
+	ast.Inspect(node, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			id, ok := astutil.Unparen(node.Fun).(*ast.Ident)
+			found = found || (ok && isBuiltinRecover(pass, id))
+		}
+
+		return !found
+	})
+
+	return found
+}
+
+// getRecoveredVar gets the variable the recovered value is assigned to, e.g. `r` in `r := recover()`.
+// A value stored anywhere else e.g. `x.last = recover()` isn't tracked.
+func getRecoveredVar(pass *analysis.Pass, node ast.Node) types.Object {
+	var lhs, rhs []ast.Expr
+	switch node := node.(type) {
+	case *ast.AssignStmt:
+		lhs, rhs = node.Lhs, node.Rhs
+	case *ast.ValueSpec:
+		rhs = node.Values
+		for _, name := range node.Names {
+			lhs = append(lhs, name)
+		}
+	}
+
+	if len(lhs) != 1 || len(rhs) != 1 || !doesNodeCallRecover(pass, rhs[0]) {
+		return nil
+	}
+
+	id, ok := lhs[0].(*ast.Ident)
+	if !ok {
+		return nil
+	}
+
+	return pass.TypesInfo.ObjectOf(id)
+}
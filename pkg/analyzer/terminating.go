@@ -0,0 +1,242 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// goexitFunc ends the Goroutine rather than the process. Its deferred calls still run, but there's no
+// panic for their recover to report.
+const goexitFunc = "runtime.Goexit"
+
+// terminatingFuncs are the functions that end the process, or the Goroutine, without unwinding it
+// through a panic, named by their full name. A recover can't stop them, so they also skip the panic
+// reporting done by the recover handler.
+//
+//nolint:gochecknoglobals
+var terminatingFuncs = []string{
+	"log.Fatal",
+	"log.Fatalf",
+	"log.Fatalln",
+	"(*log.Logger).Fatal",
+	"(*log.Logger).Fatalf",
+	"(*log.Logger).Fatalln",
+	"os.Exit",
+	goexitFunc,
+	"k8s.io/klog.Fatal",
+	"k8s.io/klog.Fatalf",
+	"k8s.io/klog.Fatalln",
+	"k8s.io/klog.Exit",
+	"k8s.io/klog/v2.Fatal",
+	"k8s.io/klog/v2.Fatalf",
+	"k8s.io/klog/v2.Fatalln",
+	"k8s.io/klog/v2.FatalS",
+	"k8s.io/klog/v2.Exit",
+	"k8s.io/klog/v2.Exitf",
+	"k8s.io/klog/v2.Exitln",
+}
+
+// getTerminatingFuncs gets the functions that terminate the process, from the defaults, the config
+// file and the flag, keyed by their full name.
+func getTerminatingFuncs() (map[string]bool, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]bool{}
+	for _, names := range [][]string{terminatingFuncs, cfg.TerminatingFuncs} {
+		for _, name := range names {
+			out[name] = true
+		}
+	}
+
+	for name := range terminatingFuncFlag {
+		out[name] = true
+	}
+
+	return out, nil
+}
+
+// isProcessTerminatingFunc checks if the function ends the process, so a recover handler that calls
+// it still crashes the program. runtime.Goexit only ends the Goroutine, which stops the panic. A
+// broken config file fails the run when the launchers are loaded, so the error is dropped here.
+func isProcessTerminatingFunc(fn *types.Func) bool {
+	terminating, err := getTerminatingFuncs()
+	return err == nil && fn.FullName() != goexitFunc && terminating[fn.FullName()]
+}
+
+// annotateTerminatingFuncs marks the functions that can terminate the process, when checkTerminating
+// is set.
+func annotateTerminatingFuncs(pass *analysis.Pass) error {
+	if !checkTerminating {
+		return nil
+	}
+
+	terminating, err := getTerminatingFuncs()
+	if err != nil {
+		return err
+	}
+
+	markUntilFixpoint(getFuncDecls(pass), func(fn types.Object, fdecl *ast.FuncDecl) bool {
+		chain := getTerminatingChain(pass, terminating, fdecl.Body)
+		if chain != nil {
+			pass.ExportObjectFact(fn, &terminatesProcessFact{Chain: chain})
+		}
+
+		return chain != nil
+	})
+
+	return nil
+}
+
+// getTerminatingChain finds the first call in the node that can terminate the process, and the calls
+// that lead from it to the terminating function e.g. `[fatal log.Fatal]`. It's nil if nothing in the
+// node can terminate the process.
+func getTerminatingChain(pass *analysis.Pass, terminating map[string]bool, node ast.Node) []string {
+	if node == nil {
+		return nil
+	}
+
+	var chain []string
+	ast.Inspect(node, func(node ast.Node) bool {
+		if _, ok := node.(*ast.GoStmt); ok || chain != nil {
+			// A nested Goroutine is reported on its own.
+			return false
+		}
+
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		callee := typeutil.StaticCallee(pass.TypesInfo, call)
+		if callee != nil {
+			chain = getCalleeChain(pass, terminating, callee)
+		}
+
+		return chain == nil
+	})
+
+	return chain
+}
+
+// getCalleeChain gets the calls that lead from calling fn to a function that terminates the process.
+func getCalleeChain(pass *analysis.Pass, terminating map[string]bool, fn *types.Func) []string {
+	if terminating[fn.FullName()] {
+		return []string{getFuncName(fn)}
+	}
+
+	origin, _ := getFunctionOrigin(fn)
+	fact := new(terminatesProcessFact)
+	if !pass.ImportObjectFact(origin, fact) {
+		return nil
+	}
+
+	return append([]string{getFuncName(fn)}, fact.Chain...)
+}
+
+// getFuncName gets the name of the function, qualified by the name of its package e.g. `log.Fatal`
+// or `log.Logger.Fatal`.
+func getFuncName(fn *types.Func) string {
+	name := fn.Name()
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		ty := recv.Type()
+		if ptr, ok := ty.(*types.Pointer); ok {
+			ty = ptr.Elem()
+		}
+
+		if named, ok := ty.(*types.Named); ok {
+			name = named.Obj().Name() + "." + name
+		}
+	}
+
+	if fn.Pkg() == nil {
+		return name
+	}
+
+	return fn.Pkg().Name() + "." + name
+}
+
+// validateTerminatingGoroutines reports the Goroutines that can terminate the process, when
+// checkTerminating is set. The process ends before the recover of the Goroutine can run, so the
+// panic is never reported.
+func validateTerminatingGoroutines(pass *analysis.Pass) error {
+	if !checkTerminating {
+		return nil
+	}
+
+	terminating, err := getTerminatingFuncs()
+	if err != nil {
+		return err
+	}
+
+	inspector, ok := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	if !ok {
+		return fmt.Errorf("Expected inspect.Analyzer to be an *inspector.Inspector, but got %T", pass.ResultOf[inspect.Analyzer])
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.GoStmt)(nil),   /* Find Goroutines */
+		(*ast.CallExpr)(nil), /* Find calls to launchers */
+	}
+
+	report := func(node ast.Node, chain []string) {
+		switch {
+		case chain == nil:
+		case chain[len(chain)-1] == goexitFunc:
+			pass.Reportf(node.Pos(), "Goroutine can exit without a panic, which its recover can't report: `%s`", strings.Join(chain, " -> "))
+		default:
+			pass.Reportf(node.Pos(), "Goroutine can terminate the process, which skips its recover: `%s`", strings.Join(chain, " -> "))
+		}
+	}
+
+	inspector.Preorder(nodeFilter, func(node ast.Node) {
+		switch node := node.(type) {
+		case *ast.GoStmt:
+			if fnLit, ok := node.Call.Fun.(*ast.FuncLit); ok {
+				report(node, getTerminatingChain(pass, terminating, fnLit.Body))
+			} else if callee := typeutil.StaticCallee(pass.TypesInfo, node.Call); callee != nil {
+				report(node, getCalleeChain(pass, terminating, callee))
+			}
+		case *ast.CallExpr:
+			// The recover of a launcher can't stop its callbacks from terminating the process either.
+			for _, i := range getLaunchedArgs(pass, node) {
+				report(node.Args[i], getFuncValueChain(pass, terminating, node.Args[i]))
+			}
+		}
+	})
+
+	return nil
+}
+
+// getFuncValueChain gets the calls that lead from running the function the expression refers to, to
+// a function that terminates the process.
+func getFuncValueChain(pass *analysis.Pass, terminating map[string]bool, x ast.Expr) []string {
+	var id *ast.Ident
+	switch x := astutil.Unparen(x).(type) {
+	case *ast.FuncLit:
+		return getTerminatingChain(pass, terminating, x.Body)
+	case *ast.Ident:
+		id = x
+	case *ast.SelectorExpr:
+		id = x.Sel
+	default:
+		return nil
+	}
+
+	fn, ok := pass.TypesInfo.Uses[id].(*types.Func)
+	if !ok {
+		return nil
+	}
+
+	return getCalleeChain(pass, terminating, fn)
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// varAssignments holds the values assigned to the package-level function variables, by this
+// package. A nil value is an assignment we can't follow e.g. taking the address of the variable, or
+// declaring it without a value.
+type varAssignments map[*types.Var][]ast.Expr
+
+// annotateSafeFuncVars marks the unexported package-level variables of function type, where every
+// function assigned to the variable by this package is safe e.g. `var onEvent = funcWithRecover`. An
+// exported variable can be assigned by any package that imports it, so it's never marked. A variable
+// can be assigned another variable.
+func annotateSafeFuncVars(pass *analysis.Pass) error {
+	markUntilFixpoint(getVarAssignments(pass), func(v *types.Var, values []ast.Expr) bool {
+		if v.Pkg() != pass.Pkg || v.Exported() || !allFuncsSafe(pass, values) {
+			return false
+		}
+
+		pass.ExportObjectFact(v, new(safeFuncVarFact))
+		return true
+	})
+
+	return nil
+}
+
+// isPackageVarSafe checks if every function assigned to the package-level variable is safe.
+func isPackageVarSafe(pass *analysis.Pass, v *types.Var) bool {
+	return pass.ImportObjectFact(v, &safeFuncVarFact{})
+}
+
+// isPackageVar checks if the variable is declared at the package level.
+func isPackageVar(v *types.Var) bool {
+	return v.Pkg() != nil && v.Parent() == v.Pkg().Scope()
+}
+
+// getVarAssignments finds every value this package assigns to a package-level variable of function
+// type, either where it's declared or through an assignment.
+func getVarAssignments(pass *analysis.Pass) varAssignments {
+	assignments := varAssignments{}
+	getVar := func(x ast.Expr) (*types.Var, bool) {
+		var id *ast.Ident
+		switch x := astutil.Unparen(x).(type) {
+		case *ast.Ident:
+			id = x
+		case *ast.SelectorExpr:
+			id = x.Sel
+		default:
+			return nil, false
+		}
+
+		v, ok := pass.TypesInfo.ObjectOf(id).(*types.Var)
+		if !ok || !isPackageVar(v) {
+			return nil, false
+		}
+
+		_, ok = v.Type().Underlying().(*types.Signature)
+		return v, ok
+	}
+
+	add := func(x ast.Expr, value ast.Expr) {
+		if v, ok := getVar(x); ok {
+			assignments[v] = append(assignments[v], value)
+		}
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch node := node.(type) {
+			case *ast.ValueSpec:
+				for i, name := range node.Names {
+					if len(node.Names) == len(node.Values) {
+						add(name, node.Values[i])
+					} else {
+						add(name, nil)
+					}
+				}
+			case *ast.AssignStmt:
+				for i, lhs := range node.Lhs {
+					if len(node.Lhs) == len(node.Rhs) {
+						add(lhs, node.Rhs[i])
+					} else {
+						add(lhs, nil)
+					}
+				}
+			case *ast.UnaryExpr:
+				if node.Op == token.AND {
+					add(node.X, nil)
+				}
+			}
+
+			return true
+		})
+	}
+
+	return assignments
+}
@@ -0,0 +1,24 @@
+// Package dependencies is checked with the check-dependencies flag, so calls to functions from other
+// packages that start a Goroutine without a recover are reported.
+package dependencies
+
+import (
+	"watcher"
+)
+
+// startWatchers calls functions from another package that start Goroutines.
+func startWatchers() { // want startWatchers:`spawnsUnsafeGoroutine\(watcher\.StartWatcher, watcher\.go:11\)`
+	watcher.StartWatcher() // want "Call to `watcher.StartWatcher` starts a Goroutine without a defer recover in `watcher.StartWatcher` at watcher.go:11"
+	watcher.Start()        // want "Call to `watcher.Start` starts a Goroutine without a defer recover in `watcher.StartWatcher` at watcher.go:11"
+	watcher.StartSafeWatcher()
+	watcher.StartTimer() // want "Call to `watcher.StartTimer` starts a Goroutine without a defer recover in `watcher.StartTimer` at watcher.go:34"
+
+	w := &watcher.Watcher{}
+	w.Start() // want "Call to `\\(\\*watcher.Watcher\\).Start` starts a Goroutine without a defer recover in `watcher.Watcher.Start` at watcher.go:42"
+}
+
+// startWatchersLater only calls a function from this package, which is reported where it calls the
+// other package.
+func startWatchersLater() { // want startWatchersLater:`spawnsUnsafeGoroutine\(watcher\.StartWatcher, watcher\.go:11\)`
+	startWatchers()
+}
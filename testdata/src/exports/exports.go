@@ -0,0 +1,51 @@
+// Package exports is checked with the check-exports flag, so the functions exported to C must have
+// a defer recover. It only carries the directives, so it builds without cgo.
+package exports
+
+import (
+	. "fmt"
+)
+
+func handlePanic() { // want handlePanic:"isRecoverHandler"
+	if r := recover(); r != nil {
+		Printf("recover: %v\n", r)
+	}
+}
+
+//export safeExport
+func safeExport(s []int) { // want safeExport:"isSafe"
+	defer handlePanic()
+
+	Println(s[0])
+}
+
+//export noPanicExport
+func noPanicExport(a, b int) int { // want noPanicExport:"noPanic"
+	return a + b
+}
+
+//export unsafeExport
+func unsafeExport(s []int) { // want "Exported function `unsafeExport` should have a defer recover, since a panic on a C thread aborts the process"
+	Println(s[0])
+}
+
+//export unprotectedExport
+func unprotectedExport(s []int) { // want "Exported function `unprotectedExport` can panic before its recover is deferred: `Println\\(s\\[0\\]\\)`"
+	Println(s[0])
+
+	defer handlePanic()
+}
+
+//export someRecoverExport
+func someRecoverExport(s []int) { // want "Exported function `someRecoverExport` should have a defer recover: recover is only deferred on some paths"
+	if len(s) > 1 {
+		defer handlePanic()
+	}
+
+	Println(s[0])
+}
+
+// notExported can panic, but isn't called from C.
+func notExported(s []int) {
+	Println(s[0])
+}
@@ -0,0 +1,37 @@
+// Package httpserver is a dependency with a struct whose handler is set to a safe function.
+package httpserver
+
+import "log"
+
+// Server runs its handler in a Goroutine.
+type Server struct {
+	Handler func()
+}
+
+// NewServer is a constructor that sets the handler to a safe function, but the handler is exported so
+// any package can set it.
+func NewServer() *Server {
+	return &Server{Handler: handle}
+}
+
+func handle() {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Println(r)
+		}
+	}()
+
+	panic("handler panicked")
+}
+
+// NewHandler is a factory that returns a safe handler.
+func NewHandler() func() {
+	return handle
+}
+
+// OnRequest is a hook that is only ever set to a safe function by this package, but it's exported so
+// any package can set it.
+var OnRequest = handle
+
+// OnError is a hook that is set to a safe function by this package, but can be set by others.
+var OnError = handle
@@ -0,0 +1,44 @@
+// Package launchers is checked with a config file and the launcher flags, which configure the
+// functions of the pool package.
+package launchers
+
+import (
+	. "fmt"
+	"pool"
+	"time"
+)
+
+func potentiallyUnsafeCode() {
+	Println("Some code that could potentially panic runs here...")
+}
+
+func handlePanic() { // want handlePanic:`isRecoverHandler`
+	if r := recover(); r != nil {
+		Printf("recover: %v\n", r)
+	}
+}
+
+// safeLaunchers passes callbacks to launchers that recover, or callbacks that recover themselves.
+func safeLaunchers(p *pool.Pool) {
+	p.SafeSubmit(potentiallyUnsafeCode)
+	p.Submit(func() {
+		defer handlePanic()
+		potentiallyUnsafeCode()
+	})
+
+	// time.AfterFunc is configured as a safe launcher by the flags.
+	time.AfterFunc(time.Second, potentiallyUnsafeCode)
+}
+
+// unsafeLaunchers passes callbacks without a recover to launchers that run them on a new Goroutine.
+func unsafeLaunchers(p *pool.Pool) {
+	p.Submit(potentiallyUnsafeCode) // want `Goroutine should have a defer recover`
+	p.Submit(func() {               // want `Goroutine should have a defer recover`
+		potentiallyUnsafeCode()
+	})
+
+	pool.Spawn(func() { // want "Calls to `pool.Spawn` are forbidden"
+		defer handlePanic()
+		potentiallyUnsafeCode()
+	})
+}
@@ -0,0 +1,35 @@
+// Package logger is a dependency with helpers that recover from a panic when deferred.
+package logger
+
+import (
+	"log"
+)
+
+// RecoverPanic logs the recovered panic.
+func RecoverPanic() {
+	if r := recover(); r != nil {
+		log.Printf("recover: %v\n", r)
+	}
+}
+
+// Flush is deferred by callers, but doesn't recover.
+func Flush() {
+	log.Println("flushing logs")
+}
+
+// Logger recovers from panics using its methods.
+type Logger struct{}
+
+// Recover logs the recovered panic.
+func (Logger) Recover() {
+	if r := recover(); r != nil {
+		log.Printf("recover: %v\n", r)
+	}
+}
+
+// RecoverPtr logs the recovered panic.
+func (*Logger) RecoverPtr() {
+	if r := recover(); r != nil {
+		log.Printf("recover: %v\n", r)
+	}
+}
@@ -0,0 +1,89 @@
+package pkg
+
+import (
+	"math/rand"
+)
+
+// safeReassignedFunc starts a Goroutine with a variable where every assignment is safe.
+func safeReassignedFunc() {
+	f := funcWithRecover
+	if rand.Intn(2) == 0 {
+		f = genericFunctionWithRecover[any]
+	}
+
+	go f()
+}
+
+// unsafeReassignedFunc starts a Goroutine with a variable where one of the assignments is unsafe.
+func unsafeReassignedFunc() {
+	f := funcWithRecover
+	if rand.Intn(2) == 0 {
+		f = potentiallyUnsafeCode
+	}
+
+	go f() // want `Goroutine should have a defer recover`
+}
+
+// safeReassignedStruct starts a Goroutine with the field of a struct where every assignment is safe.
+func safeReassignedStruct() {
+	v := myStruct{f: funcWithRecover}
+	if rand.Intn(2) == 0 {
+		v = myStruct{f: genericFunctionWithRecover[any]}
+	}
+
+	go v.f()
+}
+
+// unsafeReassignedStruct starts a Goroutine with the field of a struct that is reassigned to an
+// unsafe function.
+func unsafeReassignedStruct() {
+	v := myStruct{f: funcWithRecover}
+	if rand.Intn(2) == 0 {
+		v.f = potentiallyUnsafeCode
+	}
+
+	go v.f() // want `Goroutine should have a defer recover`
+}
+
+// unsafeReassignedInterface starts a Goroutine with an interface that may hold an unsafe method.
+func unsafeReassignedInterface() {
+	var v someInterface = myStruct{}
+	if rand.Intn(2) == 0 {
+		v = &myGenericStruct[any, any]{}
+	}
+
+	go v.safe()
+	go v.unsafe() // want `Goroutine should have a defer recover`
+}
+
+// unsafeClosureReassignedFunc starts a Goroutine with a variable a closure can reassign to an
+// unsafe function.
+func unsafeClosureReassignedFunc() {
+	f := funcWithRecover
+	reset := func() {
+		f = potentiallyUnsafeCode
+	}
+	reset()
+
+	go f() // want `Goroutine should have a defer recover`
+}
+
+// unsafeEscapedFunc starts Goroutines with variables whose address escapes, so they can be
+// reassigned through a pointer.
+func unsafeEscapedFunc() {
+	f := funcWithRecover
+	p := &f
+	*p = potentiallyUnsafeCode
+
+	go f() // want `Goroutine should have a defer recover`
+
+	g := funcWithRecover
+	setFunc(&g)
+
+	go g() // want `Goroutine should have a defer recover`
+}
+
+// setFunc sets the function at p to an unsafe function.
+func setFunc(p *func()) {
+	*p = potentiallyUnsafeCode
+}
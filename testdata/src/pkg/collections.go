@@ -0,0 +1,156 @@
+package pkg
+
+// safeHandlers only ever holds safe functions.
+var safeHandlers = []func(){funcWithRecover}
+
+// unsafeHandlers is given a function without a recover.
+var unsafeHandlers []func()
+
+// routes is a map that only ever holds safe functions.
+var routes = map[string]func(){
+	"safe": funcWithRecover,
+}
+
+// registerHandlers stores functions in the collections.
+func registerHandlers() {
+	safeHandlers = append(safeHandlers, funcWithRecover, func() {
+		defer func() {
+			_ = recover()
+		}()
+	})
+
+	unsafeHandlers = append(unsafeHandlers, potentiallyUnsafeCode)
+
+	routes["other"] = funcWithRecover
+}
+
+// safeCollections starts Goroutines with the functions stored in collections.
+func safeCollections(key string) {
+	for _, f := range safeHandlers {
+		go f()
+	}
+
+	go safeHandlers[0]()
+	go routes[key]()
+
+	jobs := make(chan func(), 1)
+	jobs <- funcWithRecover
+	go (<-jobs)()
+
+	for f := range jobs {
+		go f()
+	}
+
+	table := [2]func(){funcWithRecover}
+	table[1] = funcWithRecover
+	go table[1]()
+
+	for _, f := range []func(){funcWithRecover} {
+		go f()
+	}
+}
+
+// unsafeCollections starts Goroutines with the functions stored in collections that are given an
+// unsafe function.
+func unsafeCollections(key string, params []func()) {
+	for _, f := range unsafeHandlers {
+		go f() // want `Goroutine should have a defer recover`
+	}
+
+	go unsafeHandlers[0]() // want `Goroutine should have a defer recover`
+	go params[0]()         // want `Goroutine should have a defer recover`
+
+	jobs := make(chan func(), 1)
+	jobs <- potentiallyUnsafeCode
+	go (<-jobs)() // want `Goroutine should have a defer recover`
+
+	table := map[string]func(){}
+	table[key] = potentiallyUnsafeCode
+	go table[key]() // want `Goroutine should have a defer recover`
+
+	for _, f := range safeHandlers {
+		f = potentiallyUnsafeCode
+		go f() // want `Goroutine should have a defer recover`
+	}
+
+	copied := make([]func(), 1)
+	copy(copied, unsafeHandlers)
+	go copied[0]() // want `Goroutine should have a defer recover`
+}
+
+// aliasedHandlers is only given safe functions directly, but an alias is given an unsafe function.
+var aliasedHandlers = []func(){funcWithRecover}
+
+// sharedHandlers is passed to a function that stores an unsafe function in it.
+var sharedHandlers = map[string]func(){"safe": funcWithRecover}
+
+// extendedHandlers has spare capacity, so appending to it through another variable can store in
+// its backing array.
+var extendedHandlers = make([]func(), 1, 2)
+
+// addUnsafeRoute stores an unsafe function in the map it's given.
+func addUnsafeRoute(table map[string]func()) {
+	table["unsafe"] = potentiallyUnsafeCode
+}
+
+// aliasHandlers stores unsafe functions in collections through aliases.
+func aliasHandlers() {
+	a := aliasedHandlers
+	a[0] = potentiallyUnsafeCode
+
+	addUnsafeRoute(sharedHandlers)
+
+	extendedHandlers[0] = funcWithRecover
+	more := append(extendedHandlers[:1], potentiallyUnsafeCode)
+	_ = more
+}
+
+// unsafeAliasedCollections starts Goroutines with the functions stored in collections that are
+// given an unsafe function through an alias.
+func unsafeAliasedCollections() {
+	go aliasedHandlers[0]()       // want `Goroutine should have a defer recover`
+	go sharedHandlers["unsafe"]() // want `Goroutine should have a defer recover`
+	go extendedHandlers[1]()      // want `Goroutine should have a defer recover`
+}
+
+// primaryJobs and backupJobs store each other's elements.
+var (
+	primaryJobs = []func(){funcWithRecover}
+	backupJobs  = []func(){funcWithRecover}
+)
+
+// swapJobs stores the elements of each collection in the other.
+func swapJobs() {
+	primaryJobs[0] = backupJobs[0]
+	backupJobs[0] = primaryJobs[0]
+}
+
+// safeCyclicCollections starts Goroutines with the functions stored in collections that store each
+// other's elements.
+func safeCyclicCollections() {
+	go primaryJobs[0]()
+	go backupJobs[0]()
+}
+
+// Handlers is only given safe functions by this package, but it's exported so any package can append
+// to it.
+var Handlers = []func(){funcWithRecover}
+
+// Pool is exported, so any package can store functions in its jobs.
+type Pool struct {
+	Jobs []func()
+}
+
+// NewPool is a constructor that only stores safe functions in the jobs.
+func NewPool() *Pool { // want NewPool:"noPanic"
+	return &Pool{Jobs: []func(){funcWithRecover}}
+}
+
+// unsafeExportedCollections starts Goroutines with the functions stored in exported collections.
+func unsafeExportedCollections() {
+	for _, f := range Handlers {
+		go f() // want `Goroutine should have a defer recover`
+	}
+
+	go NewPool().Jobs[0]() // want `Goroutine should have a defer recover`
+}
@@ -0,0 +1,97 @@
+package pkg
+
+import (
+	. "fmt"
+	"math/rand"
+)
+
+// funcWithRecoverInEveryBranch is a function that defers a recover on both branches of an if.
+func funcWithRecoverInEveryBranch(n int) { // want funcWithRecoverInEveryBranch:`isSafe`
+	if n == 0 {
+		defer handlePanic()
+	} else {
+		defer func() {
+			if r := recover(); r != nil {
+				Printf("recover: %v\n", r)
+			}
+		}()
+	}
+
+	potentiallyUnsafeCode()
+}
+
+// funcWithRecoverInOneBranch is a function that only defers a recover on one branch of an if.
+func funcWithRecoverInOneBranch() {
+	if rand.Intn(2) == 0 {
+		defer handlePanic()
+	}
+
+	potentiallyUnsafeCode()
+}
+
+// safeControlFlow starts Goroutines that defer a recover on every path.
+func safeControlFlow(n int) { // want safeControlFlow:"noPanic"
+	go funcWithRecoverInEveryBranch(n)
+
+	go func() {
+		{
+			defer handlePanic()
+		}
+
+		potentiallyUnsafeCode()
+	}()
+
+	go func() {
+		switch n {
+		case 0:
+			defer handlePanic()
+		case 1:
+			defer handlePanicWithContext(nil)
+		default:
+			defer handlePanic()
+		}
+
+		potentiallyUnsafeCode()
+	}()
+}
+
+// unsafeControlFlow starts Goroutines that only defer a recover on some paths.
+func unsafeControlFlow() { // want unsafeControlFlow:"noPanic"
+	go funcWithRecoverInOneBranch() // want `Goroutine should have a defer recover`
+
+	go func() { // want `Goroutine should have a defer recover: recover is only deferred on some paths`
+		if rand.Intn(2) == 0 {
+			defer handlePanic()
+		}
+
+		potentiallyUnsafeCode()
+	}()
+
+	go func() { // want `Goroutine should have a defer recover: recover is only deferred on some paths`
+		for i := 0; i < rand.Intn(2); i++ {
+			defer handlePanic()
+		}
+
+		potentiallyUnsafeCode()
+	}()
+
+	go func() { // want `Goroutine should have a defer recover: recover is only deferred on some paths`
+		if rand.Intn(2) == 0 {
+			return
+		}
+
+		defer handlePanic()
+
+		potentiallyUnsafeCode()
+	}()
+
+	go func() { // want `Goroutine should have a defer recover: recover is only deferred on some paths`
+		if rand.Intn(2) == 0 {
+			panic("unprotected panic")
+		}
+
+		defer handlePanic()
+
+		potentiallyUnsafeCode()
+	}()
+}
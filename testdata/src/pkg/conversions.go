@@ -0,0 +1,50 @@
+package pkg
+
+// handlerFunc is a named function type, that functions can be converted to.
+type handlerFunc func()
+
+// safeConversions starts Goroutines with functions that are converted or asserted to a function
+// type.
+func safeConversions(s any) {
+	go handlerFunc(funcWithRecover)()
+	go (handlerFunc(funcWithRecover))()
+	go (funcWithRecover)()
+	go any(funcWithRecover).(func())()
+
+	var v any = funcWithRecover
+	go v.(func())()
+
+	switch h := v.(type) {
+	case func():
+		go h()
+	}
+
+	go s.(myStruct).safe()
+	go s.(*myStruct).safe()
+
+	p := &myStruct{}
+	go (*p).safe()
+
+	f := funcWithRecover
+	go (*&f)()
+}
+
+// unsafeConversions starts Goroutines with unsafe functions that are converted or asserted to a
+// function type.
+func unsafeConversions(s any) {
+	go handlerFunc(potentiallyUnsafeCode)() // want `Goroutine should have a defer recover`
+	go (potentiallyUnsafeCode)()            // want `Goroutine should have a defer recover`
+	go s.(func())()                         // want `Goroutine should have a defer recover`
+
+	var v any = potentiallyUnsafeCode
+	go v.(func())() // want `Goroutine should have a defer recover`
+
+	if h, ok := v.(func()); ok {
+		go h() // want `Goroutine should have a defer recover`
+	}
+
+	go s.(myStruct).unsafe() // want `Goroutine should have a defer recover`
+
+	p := &myStruct{}
+	go (*p).unsafe() // want `Goroutine should have a defer recover`
+}
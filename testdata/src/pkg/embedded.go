@@ -0,0 +1,92 @@
+package pkg
+
+// starter is an interface that is embedded by structs.
+type starter interface {
+	start()
+	stop()
+}
+
+// base is a struct whose methods are promoted through the structs that embed it.
+type base struct {
+	f func()
+}
+
+func (base) start() { // want start:"isSafe"
+	defer func() {
+		_ = recover()
+	}()
+
+	panic("base failed to start")
+}
+
+func (base) stop() { // want stop:"alwaysPanics"
+	panic("base failed to stop")
+}
+
+// outer embeds a struct, so its methods and fields are promoted.
+type outer struct {
+	base
+}
+
+// outerPointer embeds a pointer to a struct.
+type outerPointer struct {
+	*base
+}
+
+// nested embeds a struct that embeds another struct.
+type nested struct {
+	name string
+	outer
+}
+
+// withStarter embeds an interface, so its methods are forwarded to the value it holds.
+type withStarter struct {
+	starter
+}
+
+// safeEmbeddedMethods starts Goroutines with methods promoted through embedded structs.
+func safeEmbeddedMethods() {
+	go outer{}.start()
+	go outerPointer{&base{}}.start()
+	go nested{}.start()
+
+	o := outer{}
+	go o.start()
+}
+
+// unsafeEmbeddedMethods starts Goroutines with unsafe methods promoted through embedded structs.
+func unsafeEmbeddedMethods() {
+	go outer{}.stop()               // want `Goroutine should have a defer recover`
+	go outerPointer{&base{}}.stop() // want `Goroutine should have a defer recover`
+	go nested{}.stop()              // want `Goroutine should have a defer recover`
+}
+
+// safeEmbeddedFields starts Goroutines with fields promoted through embedded struct literals.
+func safeEmbeddedFields() {
+	go outer{base{f: funcWithRecover}}.f()
+	go outer{base: base{funcWithRecover}}.f()
+	go outerPointer{&base{f: funcWithRecover}}.f()
+	go nested{"nested", outer{base{f: funcWithRecover}}}.f()
+	go nested{outer: outer{base{f: funcWithRecover}}}.f()
+}
+
+// unsafeEmbeddedFields starts Goroutines with unsafe fields promoted through embedded struct literals.
+func unsafeEmbeddedFields() {
+	go outer{base{f: potentiallyUnsafeCode}}.f()         // want `Goroutine should have a defer recover`
+	go outer{}.f()                                       // want `Goroutine should have a defer recover`
+	go outerPointer{&base{f: potentiallyUnsafeCode}}.f() // want `Goroutine should have a defer recover`
+	go nested{name: "nested"}.f()                        // want `Goroutine should have a defer recover`
+}
+
+// safeEmbeddedInterfaces starts Goroutines with methods of embedded interfaces.
+func safeEmbeddedInterfaces(s withStarter) { // want safeEmbeddedInterfaces:"noPanic"
+	go withStarter{base{}}.start()
+	go withStarter{outer{}}.start()
+	go s.start()
+}
+
+// unsafeEmbeddedInterfaces starts Goroutines with unsafe methods of embedded interfaces.
+func unsafeEmbeddedInterfaces(s withStarter) { // want unsafeEmbeddedInterfaces:"noPanic"
+	go withStarter{base{}}.stop() // want `Goroutine should have a defer recover`
+	go s.stop()                   // want `Goroutine should have a defer recover`
+}
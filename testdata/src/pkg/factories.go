@@ -0,0 +1,65 @@
+package pkg
+
+import "httpserver"
+
+// makeWorker is a factory that returns a function literal with a recover.
+func makeWorker(n int) func() { // want makeWorker:"noPanic" makeWorker:"returnsSafeFunc"
+	return func() {
+		defer func() {
+			_ = recover()
+		}()
+
+		_ = 10 / n
+	}
+}
+
+// newHandler is a factory that returns a named function with a recover.
+func newHandler() func() { // want newHandler:"noPanic" newHandler:"returnsSafeFunc"
+	return funcWithRecover
+}
+
+// newWorkerOrHandler is a factory that returns the result of other factories.
+func newWorkerOrHandler(worker bool) func() { // want newWorkerOrHandler:"noPanic" newWorkerOrHandler:"returnsSafeFunc"
+	if worker {
+		return makeWorker(1)
+	}
+
+	return newHandler()
+}
+
+// newUnsafeHandler is a factory that can return a function without a recover.
+func newUnsafeHandler(safe bool) func() { // want newUnsafeHandler:"noPanic"
+	if safe {
+		return funcWithRecover
+	}
+
+	return potentiallyUnsafeCode
+}
+
+// newNamedHandler is a factory that returns its named result.
+func newNamedHandler() (f func()) { // want newNamedHandler:"noPanic"
+	f = funcWithRecover
+	return
+}
+
+// safeFactories starts Goroutines with the functions returned by safe factories.
+func safeFactories() { // want safeFactories:"noPanic"
+	go makeWorker(0)()
+	go newWorkerOrHandler(true)()
+	go httpserver.NewHandler()()
+
+	h := newHandler()
+	go h()
+
+	h1 := httpserver.NewHandler()
+	go h1()
+}
+
+// unsafeFactories starts Goroutines with the functions returned by factories that may not recover.
+func unsafeFactories() { // want unsafeFactories:"noPanic"
+	go newUnsafeHandler(true)() // want `Goroutine should have a defer recover`
+	go newNamedHandler()()      // want `Goroutine should have a defer recover`
+
+	h := newUnsafeHandler(true)
+	go h() // want `Goroutine should have a defer recover`
+}
@@ -0,0 +1,82 @@
+package pkg
+
+import "httpserver"
+
+// server is a struct whose handler is only ever set to safe functions.
+type server struct {
+	handler func() // want handler:"safeField"
+	// fallback is also set to a function without a recover.
+	fallback func()
+}
+
+// newServer is a constructor that sets the fields of the server.
+func newServer() *server { // want newServer:"noPanic"
+	return &server{handler: funcWithRecover, fallback: funcWithRecover}
+}
+
+// newFallbackServer is a constructor that sets the fallback to an unsafe function.
+func newFallbackServer() server { // want newFallbackServer:"noPanic"
+	return server{funcWithRecover, potentiallyUnsafeCode}
+}
+
+// setHandler assigns a safe function to the handler.
+func (s *server) setHandler() {
+	s.handler = funcWithRecover
+}
+
+// safeConstructorFields starts Goroutines using a field that is always set to a safe function.
+func safeConstructorFields() {
+	go newServer().handler()
+
+	s := newServer()
+	go s.handler()
+
+	s1 := newFallbackServer()
+	go s1.handler()
+}
+
+// unsafeConstructorFields starts Goroutines using a field that can be set to an unsafe function.
+func unsafeConstructorFields() {
+	go newServer().fallback() // want `Goroutine should have a defer recover`
+
+	s := newServer()
+	go s.fallback() // want `Goroutine should have a defer recover`
+}
+
+// client is a struct whose callback can be left as nil.
+type client struct {
+	callback func()
+}
+
+// newClient is a constructor that sets the callback of the client.
+func newClient() *client { // want newClient:"noPanic"
+	return &client{callback: funcWithRecover}
+}
+
+// unsafeNilFields starts a Goroutine using a field that may never be set.
+func unsafeNilFields() {
+	var c client
+	c.callback = funcWithRecover
+
+	go newClient().callback() // want `Goroutine should have a defer recover`
+}
+
+// Router is exported, so any package that imports it can set its route.
+type Router struct {
+	Route func()
+}
+
+// NewRouter is a constructor that sets the route to a safe function.
+func NewRouter() *Router { // want NewRouter:"noPanic"
+	return &Router{Route: funcWithRecover}
+}
+
+// unsafeExportedFields starts Goroutines using exported fields that are only set to safe functions
+// by the package that declares them, but can be set by any package that imports it.
+func unsafeExportedFields() {
+	r := NewRouter()
+	go r.Route() // want `Goroutine should have a defer recover`
+
+	s := httpserver.NewServer()
+	go s.Handler() // want `Goroutine should have a defer recover`
+}
@@ -24,7 +24,7 @@ func funcWithRecover() { // want funcWithRecover:`isSafe`
 }
 
 // funcWithOnlySafeCalls is a function composed purely of safe function
-func funcWithOnlySafeCalls() {
+func funcWithOnlySafeCalls() { // want funcWithOnlySafeCalls:`onlySafeCalls`
 	funcWithRecover()
 	funcWithRecover()
 	funcWithRecover()
@@ -39,54 +39,54 @@ func funcWithMixedCalls() {
 }
 
 // safeFunc starts a Goroutines with a safe functions.
-func safeFunc() {
+func safeFunc() { // want safeFunc:"noPanic"
 	go funcWithRecover()
 
-	// TODO: get this to pass
-	// go funcWithOnlySafeCalls()
+	go funcWithOnlySafeCalls()
 }
 
 // unsafeFunc starts a Goroutines with an unsafe functions.
-func unsafeFunc() {
+func unsafeFunc() { // want unsafeFunc:"noPanic"
 	go potentiallyUnsafeCode() // want `Goroutine should have a defer recover`
 
 	go funcWithMixedCalls() // want `Goroutine should have a defer recover`
 }
 
-// TODO fix test
-// func safeFuncShadow() {
-// 	// We shadow the function because it can cause issues
-// 	safeFunc := func() {
-// 		defer func() {
-// 			if r := recover(); r != nil {
-// 				Printf("recover: %v\n", r)
-// 			}
-// 		}()
-
-// 		Println("This should pass because it has a recover")
-// 	}
-
-// 	go safeFunc()
-// }
-
-// TODO: fix tst
-// func safeFuncShadowsUnsafe() {
-// 	// We shadow an unsafe function with a safe function
-// 	potentiallyUnsafeCode := func() {
-// 		defer func() {
-// 			if r := recover(); r != nil {
-// 				Printf("recover: %v\n", r)
-// 			}
-// 		}()
-
-// 		Println("This should pass because it has a recover")
-// 	}
-
-// 	go potentiallyUnsafeCode()
-// }
+// safeFuncShadow starts a Goroutine with a local function literal that has a recover.
+func safeFuncShadow() { // want safeFuncShadow:"noPanic"
+	// We shadow the function because it can cause issues
+	safeFunc := func() {
+		defer func() {
+			if r := recover(); r != nil {
+				Printf("recover: %v\n", r)
+			}
+		}()
+
+		Println("This should pass because it has a recover")
+	}
+
+	go safeFunc()
+}
+
+// safeFuncShadowsUnsafe starts a Goroutine with a local function literal that shadows an unsafe
+// function.
+func safeFuncShadowsUnsafe() { // want safeFuncShadowsUnsafe:"noPanic"
+	// We shadow an unsafe function with a safe function
+	potentiallyUnsafeCode := func() {
+		defer func() {
+			if r := recover(); r != nil {
+				Printf("recover: %v\n", r)
+			}
+		}()
+
+		Println("This should pass because it has a recover")
+	}
+
+	go potentiallyUnsafeCode()
+}
 
 // unsafeShadowedFunc is function that shadows a safe function with an unsafe function.
-func unsafeShadowedFunc() {
+func unsafeShadowedFunc() { // want unsafeShadowedFunc:"noPanic"
 	// We shadow a safe function with an unsafe function
 	safeFunc := func() {
 		potentiallyUnsafeCode()
@@ -95,6 +95,7 @@ func unsafeShadowedFunc() {
 	go safeFunc() // want `Goroutine should have a defer recover`
 }
 
-func callToExternalPackge() {
-	go errors.New("some error") // want `Goroutine should have a defer recover`
+func callToExternalPackge() { // want callToExternalPackge:"noPanic"
+	// errors.New can't panic, so it doesn't need a recover.
+	go errors.New("some error")
 }
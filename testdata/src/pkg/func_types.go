@@ -0,0 +1,78 @@
+package pkg
+
+// job is a named function type, whose methods call the function.
+type job func()
+
+func (j job) Run() { // want Run:"delegatesToReceiver"
+	j()
+}
+
+// runTwice calls its receiver more than once.
+func (j job) runTwice() { // want runTwice:"delegatesToReceiver"
+	j()
+	j()
+}
+
+// runLogged does more than calling its receiver, so it's checked on its own.
+func (j job) runLogged() {
+	println("running job")
+	j()
+}
+
+// task is a named function type with arguments.
+type task func(n int) int
+
+func (t task) Do(n int) int { // want Do:"delegatesToReceiver"
+	return t(n)
+}
+
+// doAt can panic on its own while getting the argument, before its receiver is called.
+func (t task) doAt(arr []int, i int) int {
+	return t(arr[i])
+}
+
+// spawnJob starts a Goroutine with a job.
+func spawnJob(j job) { // want spawnJob:"noPanic"
+	go j.Run() // want `Goroutine should have a defer recover`
+}
+
+// safeFuncTypeMethods starts Goroutines with methods that only call a safe receiver.
+func safeFuncTypeMethods() { // want safeFuncTypeMethods:"noPanic"
+	go job(funcWithRecover).Run()
+	go job(funcWithRecover).runTwice()
+
+	j := job(funcWithRecover)
+	go j.Run()
+
+	run := j.Run
+	go run()
+
+	go task(func(n int) int {
+		defer func() {
+			_ = recover()
+		}()
+
+		return 10 / n
+	}).Do(0)
+}
+
+// unsafeFuncTypeMethods starts Goroutines with methods that call an unsafe receiver.
+func unsafeFuncTypeMethods() { // want unsafeFuncTypeMethods:"noPanic"
+	go job(potentiallyUnsafeCode).Run() // want `Goroutine should have a defer recover`
+	go job(funcWithRecover).runLogged() // want `Goroutine should have a defer recover`
+
+	j := job(potentiallyUnsafeCode)
+	go j.Run() // want `Goroutine should have a defer recover`
+
+	run := j.Run
+	go run() // want `Goroutine should have a defer recover`
+
+	t := task(func(n int) int {
+		defer func() {
+			_ = recover()
+		}()
+
+		return 10 / n
+	})
+	go t.doAt(nil, 5) // want `Goroutine should have a defer recover`
+}
@@ -0,0 +1,55 @@
+package pkg
+
+import "httpserver"
+
+// onEvent is a hook that is only ever set to safe functions.
+var onEvent = funcWithRecover // want onEvent:"safeFuncVar"
+
+// onClose is a hook that is set to a function without a recover.
+var onClose = funcWithRecover
+
+// onStart is a hook declared without a value.
+var onStart func()
+
+// onStop is a hook whose address is taken.
+var onStop = funcWithRecover
+
+// OnShutdown is a hook that is only ever set to safe functions by this package, but it's exported so
+// any package that imports it can set it.
+var OnShutdown = funcWithRecover
+
+// setHooks reassigns the hooks.
+func setHooks() {
+	onEvent = func() {
+		defer func() {
+			_ = recover()
+		}()
+	}
+
+	onClose = potentiallyUnsafeCode
+	onStart = funcWithRecover
+	httpserver.OnError = potentiallyUnsafeCode
+
+	setHook(&onStop)
+}
+
+func setHook(hook *func()) {
+	*hook = potentiallyUnsafeCode
+}
+
+// safeFuncVars starts Goroutines with package-level variables that are only ever set to safe
+// functions.
+func safeFuncVars() { // want safeFuncVars:"noPanic"
+	go onEvent()
+}
+
+// unsafeFuncVars starts Goroutines with package-level variables that can be set to unsafe functions.
+func unsafeFuncVars() { // want unsafeFuncVars:"noPanic"
+	go onClose()            // want `Goroutine should have a defer recover`
+	go onStart()            // want `Goroutine should have a defer recover`
+	go onStop()             // want `Goroutine should have a defer recover`
+	go httpserver.OnError() // want `Goroutine should have a defer recover`
+
+	go OnShutdown()           // want `Goroutine should have a defer recover`
+	go httpserver.OnRequest() // want `Goroutine should have a defer recover`
+}
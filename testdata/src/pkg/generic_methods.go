@@ -8,7 +8,7 @@ type myGenericStruct[T, S any] struct {
 	f func()
 }
 
-func newMyGenericStruct[T, S any]() *myGenericStruct[T, S] {
+func newMyGenericStruct[T, S any]() *myGenericStruct[T, S] { // want newMyGenericStruct:"noPanic"
 	return &myGenericStruct[T, S]{}
 }
 
@@ -26,7 +26,7 @@ func (m myGenericStruct[T, S]) unsafe() {
 	Println("This should fail because it has no recover")
 }
 
-func (m myGenericStruct[T, S]) clone() myGenericStruct[T, S] {
+func (m myGenericStruct[T, S]) clone() myGenericStruct[T, S] { // want clone:"noPanic"
 	return m
 }
 
@@ -46,13 +46,13 @@ func unsafeGenericMethod() {
 }
 
 // safeGenericMethodAssignment starts safe Goroutines from a generic struct assigned to a variable.
-// func safeGenericMethodAssignment() {
-// 	v := myGenericStruct[any, any]{}
-// 	go v.safe()
+func safeGenericMethodAssignment() {
+	v := myGenericStruct[any, any]{}
+	go v.safe()
 
-// 	p := &myGenericStruct[any, any]{}
-// 	go p.safe()
-// }
+	p := &myGenericStruct[any, any]{}
+	go p.safe()
+}
 
 // unsafeGenericMethodAssignment starts unsafe Goroutines from a generic struct assigned to a variable.
 func unsafeGenericMethodAssignment() {
@@ -64,9 +64,9 @@ func unsafeGenericMethodAssignment() {
 }
 
 // safeGenericFields is a function that runs goroutines using the fields in a generic struct.
-func safeGenericFields() {
+func safeGenericFields() { // want safeGenericFields:"noPanic"
 	go myGenericStruct[any, any]{f: funcWithRecover}.f()
-	go struct{ f func() }{f: funcWithRecover}.f()
+	go struct{ f func() }{f: funcWithRecover}.f() // want f:"safeField"
 }
 
 // unsafeGenericFields is a function that starts unsafe Goroutine using the fields in a generic struct.
@@ -79,16 +79,15 @@ func unsafeGenericFields() {
 	go struct{ f func() }{}.f()                         // want `Goroutine should have a defer recover`
 }
 
-// TODO: fix test
-// safeGenericFieldsAssignment tarts safe Goroutines using the fields in a generic struct, where
+// safeGenericFieldsAssignment starts safe Goroutines using the fields in a generic struct, where
 // the struct was assigned to a variable.
-// func safeGenericFieldsAssignment() {
-// 	v := myGenericStruct[any, any]{f: funcWithRecover}
-// 	go v.f()
+func safeGenericFieldsAssignment() {
+	v := myGenericStruct[any, any]{f: funcWithRecover}
+	go v.f()
 
-// 	p := &myGenericStruct[any, any]{f: funcWithRecover}
-// 	go p.f()
-// }
+	p := &myGenericStruct[any, any]{f: funcWithRecover}
+	go p.f()
+}
 
 // unsafeGenericFieldsAssignment starts unsafe Goroutine using the fields in a generic struct,
 // where the struct was assigned to a variable.
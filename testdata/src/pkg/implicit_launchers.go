@@ -0,0 +1,69 @@
+package pkg
+
+import (
+	"context"
+	. "fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// safeImplicitLaunchers passes callbacks that recover to functions that run them on a new Goroutine.
+func safeImplicitLaunchers(ctx context.Context, v *myStruct, wg *sync.WaitGroup) {
+	time.AfterFunc(time.Second, funcWithRecover)
+	time.AfterFunc(time.Second, func() {
+		defer handlePanic()
+		potentiallyUnsafeCode()
+	})
+
+	f := funcWithRecover
+	time.AfterFunc(time.Second, f)
+
+	runtime.SetFinalizer(v, func(*myStruct) {
+		defer handlePanic()
+		potentiallyUnsafeCode()
+	})
+
+	// A nil finalizer clears the finalizer instead.
+	runtime.SetFinalizer(v, nil)
+
+	context.AfterFunc(ctx, funcWithRecover)
+	wg.Go(funcWithRecover)
+	(*sync.WaitGroup).Go(wg, funcWithRecover)
+	wg.Go(func() {
+		defer handlePanic()
+		potentiallyUnsafeCode()
+	})
+}
+
+// unsafeImplicitLaunchers passes callbacks without a recover to functions that run them on a new
+// Goroutine.
+func unsafeImplicitLaunchers(ctx context.Context, v *myStruct, wg *sync.WaitGroup, s []int) {
+	time.AfterFunc(time.Second, potentiallyUnsafeCode) // want `Goroutine should have a defer recover`
+	time.AfterFunc(time.Second, func() {               // want `Goroutine should have a defer recover`
+		potentiallyUnsafeCode()
+	})
+
+	time.AfterFunc(time.Second, func() { // want "Goroutine should have a defer recover: recover is deferred directly, so it is never called by the deferred function"
+		defer recover()
+		potentiallyUnsafeCode()
+	})
+
+	time.AfterFunc(time.Second, func() { // want "Goroutine can panic before its recover is deferred: `Println\\(s\\[0\\]\\)`"
+		Println(s[0])
+		defer handlePanic()
+	})
+
+	time.AfterFunc(time.Second, funcWithLateRecover) // want "Goroutine can panic before its recover is deferred: `potentiallyUnsafeCode\\(\\)`"
+
+	runtime.SetFinalizer(v, func(*myStruct) { // want `Goroutine should have a defer recover`
+		potentiallyUnsafeCode()
+	})
+
+	context.AfterFunc(ctx, potentiallyUnsafeCode)   // want `Goroutine should have a defer recover`
+	wg.Go(potentiallyUnsafeCode)                    // want `Goroutine should have a defer recover`
+	(*sync.WaitGroup).Go(wg, potentiallyUnsafeCode) // want `Goroutine should have a defer recover`
+	wg.Go(func() {                                  // want `Goroutine should have a defer recover`
+		potentiallyUnsafeCode()
+	})
+}
@@ -0,0 +1,68 @@
+package pkg
+
+import (
+	. "fmt"
+)
+
+// shadowedRecover starts a Goroutine where recover is a variable, rather than the builtin.
+func shadowedRecover() { // want shadowedRecover:"noPanic"
+	recover := func() any { return nil }
+
+	go func() { // want `Goroutine should have a defer recover: recover is shadowed, so it does not refer to the builtin`
+		defer func() {
+			if r := recover(); r != nil {
+				Printf("recover: %v\n", r)
+			}
+		}()
+
+		potentiallyUnsafeCode()
+	}()
+}
+
+// nestedRecover starts a Goroutine where recover is called one frame below the deferred function.
+func nestedRecover() { // want nestedRecover:"noPanic"
+	go func() { // want `Goroutine should have a defer recover: recover is called by a nested function literal, so it always returns nil`
+		defer func() {
+			func() {
+				if r := recover(); r != nil {
+					Printf("recover: %v\n", r)
+				}
+			}()
+		}()
+
+		potentiallyUnsafeCode()
+	}()
+}
+
+// deferredHandlerInsideDefer starts a Goroutine where a recover handler is called by the deferred
+// function, instead of being deferred itself.
+func deferredHandlerInsideDefer() { // want deferredHandlerInsideDefer:"noPanic"
+	go func() { // want `Goroutine should have a defer recover: recover handler is called by the deferred function instead of being deferred, so its recover always returns nil`
+		defer func() {
+			handlePanic()
+		}()
+
+		potentiallyUnsafeCode()
+	}()
+}
+
+// deferredRecover starts a Goroutine that defers recover directly, so recover isn't called by a
+// deferred function.
+func deferredRecover() { // want deferredRecover:"noPanic"
+	go func() { // want `Goroutine should have a defer recover: recover is deferred directly, so it is never called by the deferred function`
+		defer recover()
+
+		potentiallyUnsafeCode()
+	}()
+}
+
+// parenthesizedRecover starts a Goroutine that calls recover through parentheses.
+func parenthesizedRecover() { // want parenthesizedRecover:"noPanic"
+	go func() {
+		defer func() {
+			(recover)()
+		}()
+
+		potentiallyUnsafeCode()
+	}()
+}
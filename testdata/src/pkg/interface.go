@@ -5,39 +5,160 @@ type someInterface interface {
 	unsafe()
 }
 
-// TODO: fix test
-
 // safeMethodInInterface is a function that has a method with a recover.
-// func safeMethodInInterface() {
-// 	go someInterface(myStruct{}).safe()
-// 	go someInterface(newMyStruct()).safe()
-// 	go someInterface(newMyStruct().clone().clone()).safe()
+func safeMethodInInterface() { // want safeMethodInInterface:"noPanic"
+	go someInterface(myStruct{}).safe()
+	go someInterface(newMyStruct()).safe()
+	go someInterface(newMyStruct().clone().clone()).safe()
 
-// 	go someInterface(new(myStruct)).safe()
-// }
+	go someInterface(new(myStruct)).safe()
+}
 
 // unsafeMethodInInterface is a function that starts a Goroutine with unsafe methods.
-func unsafeMethodInInterface() {
-	go someInterface(myStruct{}).unsafe() // want `Goroutine should have a defer recover`
+func unsafeMethodInInterface() { // want unsafeMethodInInterface:"noPanic"
+	go someInterface(myStruct{}).unsafe() // want "Goroutine should have a defer recover: `unsafe` has no recover in myStruct"
 
-	go someInterface(new(myStruct)).unsafe() // want `Goroutine should have a defer recover`
+	go someInterface(new(myStruct)).unsafe() // want "Goroutine should have a defer recover: `unsafe` has no recover in \\*myStruct"
 }
 
-// Fix test
 // safeMethodInInterfaceAssignment starts safe Goroutines from structs casted to a interface.
-// func safeMethodInInterfaceAssignment() {
-// 	v := someInterface(myStruct{})
-// 	go v.safe()
+func safeMethodInInterfaceAssignment() { // want safeMethodInInterfaceAssignment:"noPanic"
+	v := someInterface(myStruct{})
+	go v.safe()
 
-// 	p := someInterface(&myStruct{})
-// 	go p.safe()
-// }
+	p := someInterface(&myStruct{})
+	go p.safe()
+}
 
 // unsafeMethodInInterfaceAssignment is a function that starts a Goroutine with unsafe methods.
-func unsafeMethodInInterfaceAssignment() {
+func unsafeMethodInInterfaceAssignment() { // want unsafeMethodInInterfaceAssignment:"noPanic"
 	v := someInterface(myStruct{})
 	go v.unsafe() // want `Goroutine should have a defer recover`
 
 	p := someInterface(&myStruct{})
 	go p.unsafe() // want `Goroutine should have a defer recover`
 }
+
+// safeMethodOfInterfaceParam starts a Goroutine with a method that is safe in every type that
+// implements the interface.
+func safeMethodOfInterfaceParam(v someInterface) { // want safeMethodOfInterfaceParam:"noPanic"
+	go v.safe()
+}
+
+// unsafeMethodOfInterfaceParam starts a Goroutine with a method that is unsafe in the types that
+// implement the interface.
+func unsafeMethodOfInterfaceParam(v someInterface) { // want unsafeMethodOfInterfaceParam:"noPanic"
+	go v.unsafe() // want "Goroutine should have a defer recover: `unsafe` has no recover in myGenericStruct, myStruct"
+}
+
+// Listener is exported, so packages that import us can implement it with types we can't check.
+type Listener interface {
+	Listen()
+}
+
+// scheduledJob isn't exported, but it's used by an exported function, so other packages can
+// implement it.
+type scheduledJob interface {
+	Do()
+}
+
+// closedTask isn't exported or used by an exported function, so every type implementing it is
+// visible.
+type closedTask interface {
+	Execute()
+}
+
+// safeHook implements every interface with safe methods.
+type safeHook struct{}
+
+func (safeHook) Listen() { // want Listen:"isSafe"
+	defer handlePanic()
+}
+
+func (safeHook) Do() { // want Do:"isSafe"
+	defer handlePanic()
+}
+
+func (safeHook) Execute() { // want Execute:"isSafe"
+	defer handlePanic()
+}
+
+func (safeHook) Perform() { // want Perform:"isSafe"
+	defer handlePanic()
+}
+
+func (safeHook) hook() { // want hook:"isSafe"
+	defer handlePanic()
+}
+
+// Schedule runs the job in a Goroutine.
+func Schedule(j scheduledJob) { // want Schedule:"noPanic"
+	go j.Do() // want "Goroutine should have a defer recover: `scheduledJob` can be implemented by packages that aren't analyzed yet"
+}
+
+// safeClosedInterface starts a Goroutine with the method of an interface only this package can
+// implement.
+func safeClosedInterface(t closedTask) { // want safeClosedInterface:"noPanic"
+	go t.Execute()
+}
+
+// unsafeOpenInterface starts a Goroutine with the method of an interface other packages can
+// implement.
+func unsafeOpenInterface(l Listener) { // want unsafeOpenInterface:"noPanic"
+	go l.Listen() // want "Goroutine should have a defer recover: `Listener` can be implemented by packages that aren't analyzed yet"
+}
+
+// assertedTask isn't exported or used by an exported function, but values of any type are asserted
+// to it.
+type assertedTask interface {
+	Perform()
+}
+
+// Perform runs the value in a Goroutine, when it's an assertedTask.
+func Perform(x any) { // want Perform:"noPanic"
+	if t, ok := x.(assertedTask); ok {
+		go t.Perform() // want "Goroutine should have a defer recover: `assertedTask` can be implemented by packages that aren't analyzed yet"
+	}
+}
+
+// Hook has an unexported method, so only this package can implement it, but another package can
+// embed safeHook and override its exported methods.
+type Hook interface {
+	Listen()
+	hook()
+}
+
+// RunHook runs the methods of the hook in Goroutines.
+func RunHook(h Hook) { // want RunHook:"noPanic"
+	go h.hook()
+	go h.Listen() // want "Goroutine should have a defer recover: `Hook` can be implemented by packages that aren't analyzed yet"
+}
+
+// closer is implemented by a type declared inside a function.
+type closer interface {
+	close()
+}
+
+// safeCloser closes safely.
+type safeCloser struct{}
+
+func (safeCloser) close() { // want close:"isSafe"
+	defer handlePanic()
+}
+
+// unsafeCloser can panic when it closes.
+type unsafeCloser struct{}
+
+func (*unsafeCloser) close() {
+	potentiallyUnsafeCode()
+}
+
+// unsafeLocalImplementation starts a Goroutine with the method of an interface that a local type
+// implements.
+func unsafeLocalImplementation(c closer) { // want unsafeLocalImplementation:"noPanic"
+	type wrappedCloser struct {
+		*unsafeCloser
+	}
+
+	go c.close() // want "Goroutine should have a defer recover: `close` has no recover in \\*unsafeCloser, wrappedCloser"
+}
@@ -0,0 +1,42 @@
+package pkg
+
+import (
+	"context"
+	"safego"
+)
+
+// goSafely safely launches its parameter in a Goroutine.
+func goSafely(f func()) { // want goSafely:"noPanic" goSafely:`launcher\(0\)`
+	go func() {
+		defer handlePanic()
+		f()
+	}()
+}
+
+// goUnprotected launches its parameter before the recover is deferred, so it isn't a launcher.
+func goUnprotected(f func()) { // want goUnprotected:"noPanic"
+	go func() { // want "Goroutine can panic before its recover is deferred: `f\\(\\)`"
+		f()
+		defer handlePanic()
+	}()
+}
+
+type pool struct{}
+
+// Submit passes its parameter on to a launcher from another package.
+func (p *pool) Submit(ctx context.Context, f func()) { // want Submit:"noPanic" Submit:"onlySafeCalls" Submit:`launcher\(1\)`
+	safego.CtxGo(ctx, f)
+}
+
+// submitLater passes its parameter on to a launcher called through a method expression.
+func submitLater(p *pool, f func()) { // want submitLater:"noPanic" submitLater:"onlySafeCalls" submitLater:`launcher\(1\)`
+	(*pool).Submit(p, context.Background(), f)
+}
+
+// launchers starts Goroutines with unsafe functions through launchers, which recover for them.
+func launchers(p *pool) { // want launchers:"noPanic" launchers:"onlySafeCalls"
+	goSafely(potentiallyUnsafeCode)
+	safego.Go(potentiallyUnsafeCode)
+	safego.CtxGo(context.Background(), potentiallyUnsafeCode)
+	p.Submit(context.Background(), potentiallyUnsafeCode)
+}
@@ -5,7 +5,7 @@ import (
 )
 
 // safeFuncLiteral tests if a function literal with a recover passes the lint checks.
-func safeFuncLiteral() {
+func safeFuncLiteral() { // want safeFuncLiteral:"noPanic"
 	go func() {
 		defer func() {
 			if r := recover(); r != nil {
@@ -18,7 +18,7 @@ func safeFuncLiteral() {
 }
 
 // unsafeFuncLiteral is a function that has a goroutine without a defer recover.
-func unsafeFuncLiteral() {
+func unsafeFuncLiteral() { // want unsafeFuncLiteral:"noPanic"
 	go func() { // want `Goroutine should have a defer recover`
 		potentiallyUnsafeCode()
 	}()
@@ -26,7 +26,7 @@ func unsafeFuncLiteral() {
 
 // safeFuncLiteralUntrackedRecoverValue is a function that has a goroutine with a recover, but
 // the recover value is not tracked.
-func safeFuncLiteralUntrackedRecoverValue() {
+func safeFuncLiteralUntrackedRecoverValue() { // want safeFuncLiteralUntrackedRecoverValue:"noPanic"
 	go func() {
 		defer func() {
 			// Bad practice, but linter's job is just to make sure panics don't bring down the host
@@ -38,7 +38,7 @@ func safeFuncLiteralUntrackedRecoverValue() {
 }
 
 // deferGoroutine is a function that has a defer statement without a recover.
-func deferGoroutine() {
+func deferGoroutine() { // want deferGoroutine:"noPanic"
 	go func() { // want `Goroutine should have a defer recover`
 		defer func() {
 			Println("Deferred but didn't recover :(")
@@ -49,7 +49,7 @@ func deferGoroutine() {
 }
 
 // nestedSafeFunc is a function that has a goroutine that starts another goroutine with a recover.
-func nestedSafeFunc() {
+func nestedSafeFunc() { // want nestedSafeFunc:"noPanic"
 	go func() {
 		defer func() {
 			if r := recover(); r != nil {
@@ -72,7 +72,7 @@ func nestedSafeFunc() {
 }
 
 // nestedUnsafeFunc is function that unsafely starts a safe Goroutine
-func nestedUnsafeFunc() {
+func nestedUnsafeFunc() { // want nestedUnsafeFunc:"noPanic"
 	go func() { // want `Goroutine should have a defer recover`
 		potentiallyUnsafeCode()
 
@@ -0,0 +1,75 @@
+package pkg
+
+// ptrReceiver is a struct with methods on a pointer receiver.
+type ptrReceiver struct{}
+
+func (p *ptrReceiver) serve() { // want serve:"isSafe"
+	defer func() {
+		_ = recover()
+	}()
+
+	panic("pointer receiver panicked")
+}
+
+func (p *ptrReceiver) crash() { // want crash:"alwaysPanics"
+	panic("pointer receiver panicked")
+}
+
+// safeMethodValues starts safe Goroutines with method values.
+func safeMethodValues() { // want safeMethodValues:"noPanic"
+	v := myStruct{}
+	h := v.safe
+	go h()
+
+	go (v.safe)()
+
+	p := &ptrReceiver{}
+	h1 := p.serve
+	go h1()
+
+	h2 := (*myStruct).safe
+	go h2(&v)
+
+	g := myGenericStruct[int, string]{}
+	h3 := g.safe
+	go h3()
+	go (g.safe)()
+}
+
+// unsafeMethodValues starts unsafe Goroutines with method values.
+func unsafeMethodValues() { // want unsafeMethodValues:"noPanic"
+	v := myStruct{}
+	h := v.unsafe
+	go h() // want `Goroutine should have a defer recover`
+
+	go (v.unsafe)() // want `Goroutine should have a defer recover`
+
+	p := &ptrReceiver{}
+	h1 := p.crash
+	go h1() // want `Goroutine should have a defer recover`
+
+	g := myGenericStruct[int, string]{}
+	h2 := g.unsafe
+	go h2() // want `Goroutine should have a defer recover`
+}
+
+// newMethodHandler is a factory that returns a safe method value through a variable.
+func newMethodHandler() func() { // want newMethodHandler:"noPanic" newMethodHandler:"returnsSafeFunc"
+	p := &ptrReceiver{}
+	h := p.serve
+
+	return h
+}
+
+// newUnsafeMethodHandler is a factory that returns an unsafe method value through a variable.
+func newUnsafeMethodHandler() func() { // want newUnsafeMethodHandler:"noPanic"
+	var h func() = myStruct{}.unsafe
+
+	return h
+}
+
+// methodValueFactories starts Goroutines with the method values returned by factories.
+func methodValueFactories() { // want methodValueFactories:"noPanic"
+	go newMethodHandler()()
+	go newUnsafeMethodHandler()() // want `Goroutine should have a defer recover`
+}
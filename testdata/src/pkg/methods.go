@@ -22,11 +22,11 @@ func (m myStruct) unsafe() {
 	Println("This should fail because it has no recover")
 }
 
-func (m myStruct) clone() myStruct {
+func (m myStruct) clone() myStruct { // want clone:"noPanic"
 	return m
 }
 
-func newMyStruct() myStruct {
+func newMyStruct() myStruct { // want newMyStruct:"noPanic"
 	return myStruct{}
 }
 
@@ -44,15 +44,14 @@ func unsafeMethod() {
 	go new(myStruct).unsafe() // want `Goroutine should have a defer recover`
 }
 
-// TODO: fix test
 // safeMethodAssignment starts safe Goroutines from methods from structs initialized to a struct.
-// func safeMethodAssignment() {
-// 	v := myStruct{}
-// 	go v.safe()
+func safeMethodAssignment() {
+	v := myStruct{}
+	go v.safe()
 
-// 	p := &myStruct{}
-// 	go p.safe()
-// }
+	p := &myStruct{}
+	go p.safe()
+}
 
 // unsafeMethodAssignment starts unsafe Goroutines from methods from structs initialized to a variable.
 func unsafeMethodAssignment() {
@@ -64,33 +63,33 @@ func unsafeMethodAssignment() {
 }
 
 // safeMethodExpression is a function that starts safe Goroutines with method expressions.
-func safeMethodExpression() {
+func safeMethodExpression() { // want safeMethodExpression:"noPanic"
 	go myStruct.safe(myStruct{})
 
 	go (*myStruct).safe(&myStruct{})
 }
 
 // unsafeMethodExpression is a function that starts unsafe Goroutines with method expression.
-func unsafeMethodExpression() {
+func unsafeMethodExpression() { // want unsafeMethodExpression:"noPanic"
 	go myStruct.unsafe(myStruct{}) // want `Goroutine should have a defer recover`
 
 	go (*myStruct).unsafe(&myStruct{}) // want `Goroutine should have a defer recover`
 }
 
 // safeFields is function that runs goroutines using the fields in a struct
-func safeFields() {
+func safeFields() { // want safeFields:"noPanic"
 	go myStruct{f: funcWithRecover}.f()
 
-	go struct{ f func() }{f: funcWithRecover}.f()
+	go struct{ f func() }{f: funcWithRecover}.f() // want f:"safeField"
 
 	go struct {
-		f func()
+		f func() // want f:"safeField"
 		g func()
 	}{funcWithRecover, potentiallyUnsafeCode}.f()
 
 	go struct {
 		f func()
-		g func()
+		g func() // want g:"safeField"
 	}{f: potentiallyUnsafeCode, g: funcWithRecover}.g()
 }
 
@@ -104,16 +103,15 @@ func unsafeFields() {
 	go struct{ f func() }{}.f()                         // want `Goroutine should have a defer recover`
 }
 
-// TODO: fix test
 // safeFieldsAssignment is function that runs goroutines using the fields from structs initialized to
 // a variable.
-// func safeFieldsAssignment() {
-// 	v := myStruct{f: funcWithRecover}
-// 	go v.f()
+func safeFieldsAssignment() {
+	v := myStruct{f: funcWithRecover}
+	go v.f()
 
-// 	p := &myStruct{f: funcWithRecover}
-// 	go p.f()
-// }
+	p := &myStruct{f: funcWithRecover}
+	go p.f()
+}
 
 // unsafeFieldsAssignment is a function that starts a Goroutine with unsafe fields from structs
 // initialized to a variable.
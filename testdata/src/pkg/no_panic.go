@@ -0,0 +1,101 @@
+package pkg
+
+import (
+	"sync/atomic"
+)
+
+// closeChannel closes a channel, which can't panic.
+func closeChannel(done chan struct{}) { // want closeChannel:"noPanic"
+	close(done)
+}
+
+// sendResult sends on a buffered channel, which can't panic.
+func sendResult(results chan int) { // want sendResult:"noPanic"
+	results <- 1
+}
+
+// incrementCounter updates a counter atomically, which can't panic.
+func incrementCounter(counter *int64) { // want incrementCounter:"noPanic"
+	atomic.AddInt64(counter, 1)
+}
+
+// incrementAndClose only calls functions that can't panic.
+func incrementAndClose(counter *int64, done chan struct{}) { // want incrementAndClose:"noPanic" incrementAndClose:"onlySafeCalls"
+	incrementCounter(counter)
+	closeChannel(done)
+}
+
+// readCounter dereferences a pointer, which panics when it's nil.
+func readCounter(counter *int64) int64 {
+	return *counter
+}
+
+// divide panics when d is zero.
+func divide(n, d int) int {
+	return n / d
+}
+
+// shiftFlags shifts by a signed count, which panics when it's negative.
+func shiftFlags(n int) int {
+	return 1 << n
+}
+
+// shiftMask shifts by an unsigned count and a constant, which can't panic.
+func shiftMask(n uint) int { // want shiftMask:"noPanic"
+	mask := 1 << n
+	mask >>= 2
+
+	return mask
+}
+
+// firstResult panics when the slice is empty.
+func firstResult(results []int) int {
+	return results[0]
+}
+
+// doneSignal closes its channel with a pointer receiver.
+type doneSignal struct {
+	ch chan struct{}
+}
+
+// signal closes the channel it's given, without reading the receiver.
+func (d *doneSignal) signal(done chan struct{}) { // want signal:"noPanic"
+	close(done)
+}
+
+// channel reads the channel through the receiver.
+func (d doneSignal) channel() chan struct{} { // want channel:"noPanic"
+	return d.ch
+}
+
+// signalDone calls a method with a pointer receiver, which is passed the pointer as it is.
+func signalDone(d *doneSignal, done chan struct{}) { // want signalDone:"noPanic" signalDone:"onlySafeCalls"
+	d.signal(done)
+}
+
+// readChannel calls a method with a value receiver, which dereferences the pointer.
+func readChannel(d *doneSignal) chan struct{} {
+	return d.channel()
+}
+
+// safeNoPanicGoroutines starts Goroutines that can't panic, so they don't need a recover.
+func safeNoPanicGoroutines(counter *int64, done chan struct{}, results chan int) { // want safeNoPanicGoroutines:"noPanic"
+	go closeChannel(done)
+	go sendResult(results)
+	go shiftMask(2)
+	go incrementCounter(counter)
+	go incrementAndClose(counter, done)
+	go func() {
+		atomic.AddInt64(counter, 1)
+		close(done)
+	}()
+}
+
+// unsafePanickingGoroutines starts Goroutines that can panic without a recover.
+func unsafePanickingGoroutines(counter *int64, n int, results []int) { // want unsafePanickingGoroutines:"noPanic"
+	go readCounter(counter)        // want `Goroutine should have a defer recover`
+	go divide(1, n)                // want `Goroutine should have a defer recover`
+	go shiftFlags(n)               // want `Goroutine should have a defer recover`
+	go firstResult(results)        // want `Goroutine should have a defer recover`
+	go func() { _ = results[n] }() // want `Goroutine should have a defer recover`
+}
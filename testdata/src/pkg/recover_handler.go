@@ -0,0 +1,117 @@
+package pkg
+
+import (
+	"context"
+	. "fmt"
+
+	"logger"
+)
+
+// handlePanic is a function that recovers when it's deferred.
+func handlePanic() { // want handlePanic:`isRecoverHandler`
+	if r := recover(); r != nil {
+		Printf("recover: %v\n", r)
+	}
+}
+
+// handlePanicWithContext is a function that recovers when it's deferred.
+func handlePanicWithContext(ctx context.Context) { // want handlePanicWithContext:`isRecoverHandler`
+	if r := recover(); r != nil {
+		Printf("recover: %v, %v\n", ctx, r)
+	}
+}
+
+// handleGenericPanic is a generic function that recovers when it's deferred.
+func handleGenericPanic[T any]() { // want handleGenericPanic:`isRecoverHandler` handleGenericPanic:"noPanic"
+	recover()
+}
+
+// handlePanic is a method that recovers when it's deferred.
+func (m myStruct) handlePanic() { // want handlePanic:`isRecoverHandler` handlePanic:"noPanic"
+	recover()
+}
+
+// funcWithDeferredHandler is a function that recovers through a deferred handler.
+func funcWithDeferredHandler() { // want funcWithDeferredHandler:`isSafe`
+	defer handlePanic()
+
+	potentiallyUnsafeCode()
+}
+
+// safeDeferredHandler starts Goroutines that recover through deferred handlers.
+func safeDeferredHandler() { // want safeDeferredHandler:"noPanic"
+	go funcWithDeferredHandler()
+
+	go func() {
+		defer handlePanicWithContext(context.Background())
+
+		potentiallyUnsafeCode()
+	}()
+
+	go func() {
+		defer handleGenericPanic[any]()
+
+		potentiallyUnsafeCode()
+	}()
+
+	go func() {
+		defer myStruct{}.handlePanic()
+
+		potentiallyUnsafeCode()
+	}()
+
+	go func() {
+		defer myStruct.handlePanic(myStruct{})
+
+		potentiallyUnsafeCode()
+	}()
+}
+
+// safeDeferredHandlerFromPackage starts Goroutines that recover through handlers from another package.
+func safeDeferredHandlerFromPackage() { // want safeDeferredHandlerFromPackage:"noPanic"
+	go func() {
+		defer logger.RecoverPanic()
+
+		potentiallyUnsafeCode()
+	}()
+
+	l := logger.Logger{}
+	go func() {
+		defer l.Recover()
+
+		potentiallyUnsafeCode()
+	}()
+
+	go func() {
+		defer l.RecoverPtr()
+
+		potentiallyUnsafeCode()
+	}()
+
+	go func() {
+		defer logger.Logger.Recover(l)
+
+		potentiallyUnsafeCode()
+	}()
+}
+
+// unsafeDeferredHandler starts Goroutines that defer functions that don't recover.
+func unsafeDeferredHandler() { // want unsafeDeferredHandler:"noPanic"
+	go func() { // want `Goroutine should have a defer recover`
+		defer potentiallyUnsafeCode()
+
+		potentiallyUnsafeCode()
+	}()
+
+	go func() { // want `Goroutine should have a defer recover`
+		defer logger.Flush()
+
+		potentiallyUnsafeCode()
+	}()
+
+	go func() { // want `Goroutine should have a defer recover`
+		defer myStruct{}.unsafe()
+
+		potentiallyUnsafeCode()
+	}()
+}
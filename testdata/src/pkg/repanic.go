@@ -0,0 +1,151 @@
+package pkg
+
+import (
+	. "fmt"
+	"log"
+	"os"
+)
+
+// fail is a function that always panics.
+func fail(msg string) { // want fail:`alwaysPanics`
+	panic(msg)
+}
+
+// failWithLog is a function that always panics, by calling a function that always panics.
+func failWithLog(msg string) { // want failWithLog:`alwaysPanics`
+	Println(msg)
+	fail(msg)
+}
+
+// failAndRecover panics, but recovers from it, so it returns normally.
+func failAndRecover() { // want failAndRecover:`isSafe`
+	defer handlePanic()
+
+	panic("recovered")
+}
+
+// checkForever only panics on some iterations of a loop that can keep running.
+func checkForever(ready func() bool) {
+	for {
+		if !ready() {
+			panic("not ready")
+		}
+	}
+}
+
+// rethrowPanic is a function that recovers, but always panics again.
+func rethrowPanic() {
+	if r := recover(); r != nil {
+		Printf("recover: %v\n", r)
+		panic(r)
+	}
+}
+
+// funcWithRethrowingRecover is a function with a recover that always panics again.
+func funcWithRethrowingRecover() {
+	defer func() {
+		if r := recover(); r != nil {
+			panic(r)
+		}
+	}()
+
+	potentiallyUnsafeCode()
+}
+
+// funcWithConditionalRethrow is a function with a recover that only panics again for some values.
+func funcWithConditionalRethrow() { // want funcWithConditionalRethrow:`isSafe`
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(error); ok {
+				panic(r)
+			}
+
+			Printf("recover: %v\n", r)
+		}
+	}()
+
+	potentiallyUnsafeCode()
+}
+
+// panicLog keeps the last value recovered from a panic.
+type panicLog struct {
+	last any
+}
+
+// safeRethrow starts Goroutines with recovers that don't always panic again.
+func safeRethrow(x *panicLog) { // want safeRethrow:"noPanic"
+	go funcWithConditionalRethrow()
+
+	go func() {
+		defer func() {
+			x.last = recover()
+		}()
+
+		potentiallyUnsafeCode()
+	}()
+
+	go func() {
+		defer func() {
+			r := recover()
+			if r == nil {
+				panic("only panics when nothing was recovered")
+			}
+		}()
+
+		potentiallyUnsafeCode()
+	}()
+}
+
+// unsafeRethrow starts Goroutines with recovers that always panic again or exit the process.
+func unsafeRethrow() { // want unsafeRethrow:"noPanic"
+	go funcWithRethrowingRecover() // want "Goroutine should have a defer recover: recover is always followed by `panic\\(r\\)`, so the panic still crashes the program"
+
+	go func() { // want "Goroutine should have a defer recover: recover is always followed by `panic\\(r\\)`, so the panic still crashes the program"
+		defer rethrowPanic()
+
+		potentiallyUnsafeCode()
+	}()
+
+	go func() { // want "Goroutine should have a defer recover: recover is always followed by `log.Panicf\\(\"recover: %v\", r\\)`"
+		defer func() {
+			if r := recover(); r != nil {
+				log.Panicf("recover: %v", r)
+			}
+		}()
+
+		potentiallyUnsafeCode()
+	}()
+
+	go func() { // want "Goroutine should have a defer recover: recover is always followed by `log.Fatal\\(r\\)`"
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			log.Fatal(r)
+		}()
+
+		potentiallyUnsafeCode()
+	}()
+
+	go func() { // want "Goroutine should have a defer recover: recover is always followed by `os.Exit\\(1\\)`"
+		defer func() {
+			if r := recover(); r != nil {
+				os.Exit(1)
+			}
+		}()
+
+		potentiallyUnsafeCode()
+	}()
+
+	go func() { // want "Goroutine should have a defer recover: recover is always followed by `failWithLog\\(\"recovered\"\\)`"
+		defer func() {
+			if r := recover(); r != nil {
+				failWithLog("recovered")
+			}
+		}()
+
+		potentiallyUnsafeCode()
+	}()
+}
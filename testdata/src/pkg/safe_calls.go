@@ -0,0 +1,42 @@
+package pkg
+
+import (
+	. "fmt"
+
+	"worker"
+)
+
+// funcWithRecoverAndArg is a function with an argument that has a recovery handler.
+func funcWithRecoverAndArg(n int) { // want funcWithRecoverAndArg:`isSafe`
+	defer func() {
+		if r := recover(); r != nil {
+			Printf("recover: %v\n", r)
+		}
+	}()
+
+	Println(n)
+}
+
+// funcWithNestedSafeCalls only calls functions that only call safe functions.
+func funcWithNestedSafeCalls() { // want funcWithNestedSafeCalls:`onlySafeCalls`
+	funcWithOnlySafeCalls()
+	myStruct{}.safe()
+	funcWithRecoverAndArg(len("safe"))
+	worker.RunTwice()
+}
+
+// funcWithUnsafeArgs only calls safe functions, but evaluating the argument can panic.
+func funcWithUnsafeArgs(s []int) {
+	funcWithRecoverAndArg(s[0])
+}
+
+// safeCalls starts Goroutines with functions that only call safe functions.
+func safeCalls() { // want safeCalls:"noPanic"
+	go funcWithNestedSafeCalls()
+	go worker.RunTwice()
+}
+
+// unsafeCalls starts Goroutines with functions that call safe functions in an unsafe way.
+func unsafeCalls(s []int) { // want unsafeCalls:"noPanic"
+	go funcWithUnsafeArgs(s) // want `Goroutine should have a defer recover`
+}
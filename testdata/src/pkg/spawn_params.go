@@ -0,0 +1,95 @@
+package pkg
+
+import (
+	. "fmt"
+	"spawner"
+)
+
+// spawn starts a Goroutine with its parameter, so the callers are checked instead.
+func spawn(f func()) { // want spawn:"noPanic" spawn:`spawnsParam\(0\)`
+	go f()
+}
+
+// spawnSecond starts a Goroutine with its second parameter.
+func spawnSecond(name string, f func()) { // want spawnSecond:`spawnsParam\(1\)`
+	Println(name)
+	go f()
+}
+
+// forwardSpawn passes its parameter on to a function that starts a Goroutine with it.
+func forwardSpawn(f func()) { // want forwardSpawn:"noPanic" forwardSpawn:"onlySafeCalls" forwardSpawn:`spawnsParam\(0\)`
+	spawn(f)
+}
+
+// spawnReassigned reassigns its parameter before starting a Goroutine with it.
+func spawnReassigned(f func()) { // want spawnReassigned:"noPanic"
+	f = potentiallyUnsafeCode
+	go f() // want `Goroutine should have a defer recover`
+}
+
+// safeSpawnArgs passes safe functions to functions that start a Goroutine with them.
+func safeSpawnArgs() {
+	spawn(funcWithRecover)
+	spawn(func() {
+		defer handlePanic()
+		potentiallyUnsafeCode()
+	})
+
+	f := funcWithRecover
+	spawn(f)
+
+	spawnSecond("worker", funcWithRecover)
+	forwardSpawn(funcWithRecover)
+	spawner.Start(funcWithRecover)
+}
+
+// unsafeSpawnArgs passes unsafe functions to functions that start a Goroutine with them.
+func unsafeSpawnArgs() {
+	spawn(potentiallyUnsafeCode)                 // want "Goroutine in `spawn` should have a defer recover: `potentiallyUnsafeCode` has no recover"
+	spawn(func() { potentiallyUnsafeCode() })    // want "Goroutine in `spawn` should have a defer recover: `func\\(\\) { potentiallyUnsafeCode\\(\\) }` has no recover"
+	spawnSecond("worker", potentiallyUnsafeCode) // want "Goroutine in `spawnSecond` should have a defer recover: `potentiallyUnsafeCode` has no recover"
+	forwardSpawn(potentiallyUnsafeCode)          // want "Goroutine in `forwardSpawn` should have a defer recover: `potentiallyUnsafeCode` has no recover"
+	spawner.Start(potentiallyUnsafeCode)         // want "Goroutine in `Start` should have a defer recover: `potentiallyUnsafeCode` has no recover"
+}
+
+// paramStarter starts a Goroutine with the function it's given.
+type paramStarter interface {
+	start(f func())
+}
+
+// spawnStarter starts a Goroutine with its parameter.
+type spawnStarter struct{}
+
+// start can be called through the paramStarter interface, where the argument isn't checked, so the
+// Goroutine is checked where it's started instead.
+func (spawnStarter) start(f func()) { // want start:"noPanic" start:`spawnsParam\(0\)`
+	go f() // want `Goroutine should have a defer recover`
+}
+
+// spawnValue is called through a variable, where the argument isn't checked.
+func spawnValue(f func()) { // want spawnValue:"noPanic" spawnValue:`spawnsParam\(0\)`
+	go f() // want `Goroutine should have a defer recover`
+}
+
+// unsafeDynamicSpawnArgs passes an unsafe function to spawners it doesn't call directly.
+func unsafeDynamicSpawnArgs() {
+	var s paramStarter = spawnStarter{}
+	s.start(potentiallyUnsafeCode)
+
+	run := spawnValue
+	run(potentiallyUnsafeCode)
+}
+
+// taskRunner starts Goroutines with the functions it's given.
+type taskRunner struct{}
+
+func (*taskRunner) spawnTask(f func()) { // want spawnTask:"noPanic" spawnTask:`spawnsParam\(0\)`
+	go f()
+}
+
+// spawnMethodExprArgs passes functions to a spawner called through a method expression, which takes
+// the receiver as its first argument.
+func spawnMethodExprArgs(t *taskRunner) { // want spawnMethodExprArgs:"noPanic" spawnMethodExprArgs:"onlySafeCalls"
+	(*taskRunner).spawnTask(t, funcWithRecover)
+	(*taskRunner).spawnTask(t, potentiallyUnsafeCode) // want "Goroutine in `spawnTask` should have a defer recover: `potentiallyUnsafeCode` has no recover"
+}
@@ -0,0 +1,122 @@
+package pkg
+
+import (
+	"spawner"
+)
+
+type safeRunner interface {
+	safe()
+}
+
+type unsafeRunner interface {
+	unsafe()
+}
+
+// runSafe starts a Goroutine with the safe method of its type argument.
+func runSafe[T safeRunner](t T) { // want runSafe:"noPanic" runSafe:`typeParamObligations\(T\.safe\)`
+	go t.safe()
+}
+
+// runUnsafe starts a Goroutine with the unsafe method of its type argument.
+func runUnsafe[T unsafeRunner](t T) { // want runUnsafe:"noPanic" runUnsafe:`typeParamObligations\(T\.unsafe\)`
+	go t.unsafe()
+}
+
+// spawnGeneric starts a Goroutine with a function whose type is a type parameter.
+func spawnGeneric[F ~func()](f F) { // want spawnGeneric:"noPanic" spawnGeneric:`typeParamObligations\(f F\)`
+	go f()
+}
+
+// forwardRunSafe passes its type parameter on to a generic function that starts a Goroutine.
+func forwardRunSafe[S safeRunner](s S) { // want forwardRunSafe:"noPanic" forwardRunSafe:"onlySafeCalls" forwardRunSafe:`typeParamObligations\(S\.safe\)`
+	runSafe(s)
+}
+
+// forwardSpawnGeneric passes its parameter on to a generic function that starts a Goroutine.
+func forwardSpawnGeneric[G ~func()](g G) { // want forwardSpawnGeneric:"noPanic" forwardSpawnGeneric:"onlySafeCalls" forwardSpawnGeneric:`typeParamObligations\(g G\)`
+	spawnGeneric(g)
+}
+
+type safeJob struct{}
+
+func (safeJob) Run() { // want Run:`isSafe`
+	defer func() {
+		recover()
+	}()
+
+	potentiallyUnsafeCode()
+}
+
+type unsafeJob struct{}
+
+func (unsafeJob) Run() {
+	potentiallyUnsafeCode()
+}
+
+// safeInstantiations instantiates generic functions with type arguments that are safe.
+func safeInstantiations() { // want safeInstantiations:"noPanic"
+	runSafe(myStruct{})
+	runSafe(&myGenericStruct[any, any]{})
+	runSafe[someInterface](myStruct{})
+
+	spawnGeneric(funcWithRecover)
+	spawnGeneric(func() {
+		defer handlePanic()
+		potentiallyUnsafeCode()
+	})
+
+	f := funcWithRecover
+	spawnGeneric(f)
+
+	forwardRunSafe(myStruct{})
+	forwardSpawnGeneric(funcWithRecover)
+
+	spawner.RunAll(safeJob{}, safeJob{})
+	spawner.Spawn(funcWithRecover)
+}
+
+// unsafeInstantiations instantiates generic functions with type arguments that are unsafe.
+func unsafeInstantiations() { // want unsafeInstantiations:"noPanic" unsafeInstantiations:"onlySafeCalls"
+	runUnsafe(myStruct{})                      // want "Goroutine in `runUnsafe` should have a defer recover: `unsafe` has no recover in myStruct"
+	runUnsafe[someInterface](myStruct{})       // want "Goroutine in `runUnsafe` should have a defer recover: `unsafe` has no recover in myGenericStruct, myStruct"
+	spawnGeneric(potentiallyUnsafeCode)        // want "Goroutine in `spawnGeneric` should have a defer recover: `potentiallyUnsafeCode` has no recover"
+	forwardSpawnGeneric(potentiallyUnsafeCode) // want "Goroutine in `forwardSpawnGeneric` should have a defer recover: `potentiallyUnsafeCode` has no recover"
+
+	spawner.RunAll(unsafeJob{})          // want "Goroutine in `RunAll` should have a defer recover: `Run` has no recover in unsafeJob"
+	spawner.Spawn(potentiallyUnsafeCode) // want "Goroutine in `Spawn` should have a defer recover: `potentiallyUnsafeCode` has no recover"
+}
+
+// jobQueue holds jobs of a type parameter that only its methods can use.
+type jobQueue[J safeRunner] struct {
+	jobs []J
+}
+
+// start passes the type parameter of its receiver on to a generic function that starts a
+// Goroutine, but the type arguments of the receiver aren't checked where the method is called.
+func (q jobQueue[J]) start() { // want start:"noPanic"
+	for _, j := range q.jobs {
+		runSafe(j) // want "Goroutine in `runSafe` should have a defer recover: `J` is a type parameter, so the types it's instantiated with can't be checked"
+	}
+}
+
+// forwardCopiedParam passes a copy of its parameter on to a generic function that starts a
+// Goroutine, so the copy is checked instead of the argument.
+func forwardCopiedParam[F ~func()](f F) { // want forwardCopiedParam:"noPanic"
+	g := f
+	spawnGeneric(g) // want "Goroutine in `spawnGeneric` should have a defer recover: `g` has no recover"
+}
+
+// spawnLater starts a Goroutine with its parameter, but it's also called through a value, where its
+// type arguments aren't checked.
+func spawnLater[F ~func()](f F) { // want spawnLater:"noPanic" spawnLater:`typeParamObligations\(f F\)`
+	go f() // want "Goroutine should have a defer recover"
+}
+
+// unsafeGenericValue instantiates a generic function that starts a Goroutine, and calls it through a
+// value.
+func unsafeGenericValue() {
+	forwardCopiedParam(potentiallyUnsafeCode)
+
+	s := spawnLater[func()]
+	s(potentiallyUnsafeCode)
+}
@@ -0,0 +1,103 @@
+package pkg
+
+import "worker"
+
+// funcWithLateRecover is a function that calls a function before it defers its recover.
+func funcWithLateRecover() {
+	potentiallyUnsafeCode()
+
+	defer handlePanic()
+
+	potentiallyUnsafeCode()
+}
+
+// safePrelude starts Goroutines that run statements that can't panic before deferring a recover.
+func safePrelude(s []int, m map[string]int, i any, n int) { // want safePrelude:"noPanic"
+	go func() {
+		size := len(s)
+		v, ok := i.(int)
+		count := m["count"]
+		ch := make(chan int, 1)
+		var p *int
+		x := n / 2
+		y := n >> 1
+
+		defer handlePanic()
+
+		potentiallyUnsafeCode()
+		_, _, _, _, _, _, _, _ = size, v, ok, count, ch, p, x, y
+	}()
+}
+
+// unsafePrelude starts Goroutines that run statements that can panic before deferring a recover.
+func unsafePrelude(s []int, m map[string]int, i any, p *int, n int) { // want unsafePrelude:"noPanic"
+	go funcWithLateRecover() // want "Goroutine can panic before its recover is deferred: `potentiallyUnsafeCode\\(\\)`"
+
+	go func() { // want "Goroutine can panic before its recover is deferred: `first := s\\[0\\]`"
+		first := s[0]
+
+		defer handlePanic()
+
+		_ = first
+	}()
+
+	go func() { // want "Goroutine can panic before its recover is deferred: `m\\[\"count\"\\] = 1`"
+		m["count"] = 1
+
+		defer handlePanic()
+	}()
+
+	go func() { // want "Goroutine can panic before its recover is deferred: `v := i.\\(int\\)`"
+		v := i.(int)
+
+		defer handlePanic()
+
+		_ = v
+	}()
+
+	go func() { // want "Goroutine can panic before its recover is deferred: `v := \\*p`"
+		v := *p
+
+		defer handlePanic()
+
+		_ = v
+	}()
+
+	go func() { // want "Goroutine can panic before its recover is deferred: `v := 10 / n`"
+		v := 10 / n
+
+		defer handlePanic()
+
+		_ = v
+	}()
+
+	go func() { // want "Goroutine can panic before its recover is deferred: `mask := 1 << n`"
+		mask := 1 << n
+
+		defer handlePanic()
+
+		_ = mask
+	}()
+
+	go func() { // want "Goroutine can panic before its recover is deferred: `n > len\\(s\\[1:\\]\\)`"
+		if n > len(s[1:]) {
+			n = 0
+		}
+
+		defer handlePanic()
+
+		_ = n
+	}()
+}
+
+// lateRecoverWrapper only calls a function that can panic before its recover is deferred.
+func lateRecoverWrapper() {
+	funcWithLateRecover()
+}
+
+// unsafeLateRecoverCalls starts Goroutines with functions that can panic before a recover is
+// deferred.
+func unsafeLateRecoverCalls() { // want unsafeLateRecoverCalls:"noPanic"
+	go lateRecoverWrapper() // want `Goroutine should have a defer recover`
+	go worker.Late()        // want `Goroutine should have a defer recover`
+}
@@ -0,0 +1,23 @@
+// Package pool is a dependency with a worker pool, that runs callbacks on its own Goroutines.
+package pool
+
+// Pool runs the submitted tasks on its workers.
+type Pool struct {
+	tasks chan func()
+}
+
+// Submit runs the task on one of the workers.
+func (p *Pool) Submit(task func()) {
+	p.tasks <- task
+}
+
+// SafeSubmit runs the task on one of the workers, which recover if it panics.
+func (p *Pool) SafeSubmit(task func()) {
+	p.tasks <- task
+}
+
+// Spawn runs the task on a new worker.
+func Spawn(task func()) {
+	p := &Pool{tasks: make(chan func(), 1)}
+	p.Submit(task)
+}
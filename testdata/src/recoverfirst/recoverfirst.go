@@ -0,0 +1,33 @@
+// Package recoverfirst is checked with the recover-first flag, so a Goroutine must defer its recover
+// before anything else.
+package recoverfirst
+
+import (
+	. "fmt"
+)
+
+func handlePanic() { // want handlePanic:`isRecoverHandler`
+	if r := recover(); r != nil {
+		Printf("recover: %v\n", r)
+	}
+}
+
+// safeRecoverFirst starts a Goroutine that defers the recover before anything else.
+func safeRecoverFirst(s []int) { // want safeRecoverFirst:"noPanic"
+	go func() {
+		defer handlePanic()
+
+		Println(s[0])
+	}()
+}
+
+// unsafeRecoverFirst starts a Goroutine that runs statements that can't panic before its recover.
+func unsafeRecoverFirst(s []int) { // want unsafeRecoverFirst:"noPanic"
+	go func() { // want "Goroutine can panic before its recover is deferred: `size := len\\(s\\)`"
+		size := len(s)
+
+		defer handlePanic()
+
+		Println(size)
+	}()
+}
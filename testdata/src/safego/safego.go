@@ -0,0 +1,34 @@
+// Package safego is a dependency that safely launches Goroutines, like the README recommends.
+package safego
+
+import (
+	"context"
+	"log"
+)
+
+// Go safely launches a Goroutine, so that a panic doesn't bring down the host.
+func Go(f func()) {
+	go func() {
+		defer func() {
+			if err := recover(); err != nil {
+				log.Printf("recover: %v\n", err)
+			}
+		}()
+
+		f()
+	}()
+}
+
+// CtxGo safely launches a Goroutine, so that a panic doesn't bring down the host.
+// The context is used to improve our observability.
+func CtxGo(ctx context.Context, f func()) {
+	go func() {
+		defer func() {
+			if err := recover(); err != nil {
+				log.Printf("recover: %v, %v\n", ctx, err)
+			}
+		}()
+
+		f()
+	}()
+}
@@ -0,0 +1,18 @@
+// Package shutdown is a dependency with functions that terminate the process.
+package shutdown
+
+import "os"
+
+// Abort exits the process through a helper.
+func Abort(code int) {
+	exit(code)
+}
+
+func exit(code int) {
+	os.Exit(code)
+}
+
+// Crash is configured as a function that terminates the process.
+func Crash(msg string) {
+	println(msg)
+}
@@ -0,0 +1,25 @@
+// Package spawner is a dependency with generic functions that start Goroutines with their type
+// arguments.
+package spawner
+
+// Runner is a job that can be run in a Goroutine.
+type Runner interface {
+	Run()
+}
+
+// RunAll runs the job in a Goroutine, which is only safe when the job recovers.
+func RunAll[T Runner](jobs ...T) {
+	for _, job := range jobs {
+		go job.Run()
+	}
+}
+
+// Spawn runs f in a Goroutine, which is only safe when f recovers.
+func Spawn[F ~func()](f F) {
+	go f()
+}
+
+// Start runs f in a Goroutine, which is only safe when f recovers.
+func Start(f func()) {
+	go f()
+}
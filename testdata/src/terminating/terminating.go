@@ -0,0 +1,121 @@
+// Package terminating is checked with the check-terminating flag, so a Goroutine must not terminate
+// the process before its recover can run.
+package terminating
+
+import (
+	"log"
+	"os"
+	"runtime"
+	"safego"
+	"shutdown"
+)
+
+func handlePanic() { // want handlePanic:"isRecoverHandler"
+	if r := recover(); r != nil {
+		log.Println(r)
+	}
+}
+
+func fatal(err error) { // want fatal:"alwaysPanics" fatal:`terminatesProcess\(log\.Fatal\)`
+	log.Fatal(err)
+}
+
+func fatalLater(err error) { // want fatalLater:`terminatesProcess\(terminating\.fatal \-> log\.Fatal\)`
+	if err != nil {
+		fatal(err)
+	}
+}
+
+// startFatal starts a Goroutine that terminates the process, which is reported on its own.
+func startFatal(err error) { // want startFatal:"noPanic"
+	go func() { // want "Goroutine can terminate the process, which skips its recover: `log.Fatal`"
+		defer handlePanic()
+
+		log.Fatal(err)
+	}()
+}
+
+// safeGoroutines starts Goroutines that never terminate the process.
+func safeGoroutines(err error) { // want safeGoroutines:"noPanic"
+	go func() {
+		defer handlePanic()
+
+		log.Println(err)
+	}()
+
+	go startFatal(err)
+}
+
+// terminatingGoroutines starts Goroutines that can terminate the process.
+func terminatingGoroutines(err error) { // want terminatingGoroutines:"noPanic"
+	go func() { // want "Goroutine can terminate the process, which skips its recover: `os.Exit`"
+		defer handlePanic()
+
+		os.Exit(1)
+	}()
+
+	go func() { // want "Goroutine can terminate the process, which skips its recover: `terminating.fatalLater -> terminating.fatal -> log.Fatal`"
+		defer handlePanic()
+
+		fatalLater(err)
+	}()
+
+	go fatalLater(err) // want `Goroutine should have a defer recover` "Goroutine can terminate the process, which skips its recover: `terminating.fatalLater -> terminating.fatal -> log.Fatal`"
+
+	go func() { // want "Goroutine can exit without a panic, which its recover can't report: `runtime.Goexit`"
+		defer handlePanic()
+
+		runtime.Goexit()
+	}()
+
+	go func() { // want "Goroutine can terminate the process, which skips its recover: `log.Logger.Fatalf`"
+		defer handlePanic()
+
+		log.Default().Fatalf("failed: %v", err)
+	}()
+}
+
+// crashOnPanic recovers, but always terminates the process with a configured function.
+func crashOnPanic() { // want crashOnPanic:"noPanic" crashOnPanic:`terminatesProcess\(shutdown\.Crash\)`
+	if r := recover(); r != nil {
+		shutdown.Crash("recovered")
+	}
+}
+
+// terminatingHandlers starts Goroutines whose recover handler terminates the process.
+func terminatingHandlers(n int) { // want terminatingHandlers:"noPanic"
+	go func() { // want "Goroutine should have a defer recover: recover is always followed by `shutdown.Crash\\(\"recovered\"\\)`" "Goroutine can terminate the process, which skips its recover: `terminating.crashOnPanic -> shutdown.Crash`"
+		defer crashOnPanic()
+
+		println(10 / n)
+	}()
+}
+
+// terminatingDependencies starts Goroutines that terminate the process in a dependency.
+func terminatingDependencies() { // want terminatingDependencies:"noPanic"
+	go func() { // want "Goroutine can terminate the process, which skips its recover: `shutdown.Abort -> shutdown.exit -> os.Exit`"
+		defer handlePanic()
+
+		shutdown.Abort(1)
+	}()
+
+	go func() { // want "Goroutine can terminate the process, which skips its recover: `shutdown.Crash`"
+		defer handlePanic()
+
+		shutdown.Crash("crashed")
+	}()
+}
+
+// terminatingLaunchers passes callbacks that terminate the process to a launcher, whose recover can't
+// stop them either.
+func terminatingLaunchers(err error) { // want terminatingLaunchers:"noPanic" terminatingLaunchers:"onlySafeCalls" terminatingLaunchers:`terminatesProcess\(log\.Fatal\)`
+	safego.Go(func() {
+		log.Println(err)
+	})
+
+	safego.Go(func() { // want "Goroutine can terminate the process, which skips its recover: `log.Fatal`"
+		log.Fatal(err)
+	})
+
+	safego.Go(func() { fatalLater(err) }) // want "Goroutine can terminate the process, which skips its recover: `terminating.fatalLater -> terminating.fatal -> log.Fatal`"
+}
@@ -0,0 +1,47 @@
+// Package watcher is a dependency that starts Goroutines in the background.
+package watcher
+
+import (
+	"log"
+	"time"
+)
+
+// StartWatcher starts a Goroutine without a defer recover.
+func StartWatcher() {
+	go loop()
+}
+
+// Start starts a watcher through another function.
+func Start() {
+	StartWatcher()
+}
+
+// StartSafeWatcher starts a Goroutine that recovers.
+func StartSafeWatcher() {
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("recover: %v\n", r)
+			}
+		}()
+
+		loop()
+	}()
+}
+
+// StartTimer runs the watcher on a new Goroutine after a second.
+func StartTimer() {
+	time.AfterFunc(time.Second, loop)
+}
+
+// Watcher starts watchers with its methods.
+type Watcher struct{}
+
+// Start starts a Goroutine without a defer recover.
+func (w *Watcher) Start() {
+	go loop()
+}
+
+func loop() {
+	log.Println("watching...")
+}
@@ -0,0 +1,36 @@
+// Package worker is a dependency with functions that are safe to run in a Goroutine.
+package worker
+
+import (
+	"log"
+)
+
+// Run does some work, and recovers if it panics.
+func Run() {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("recover: %v\n", r)
+		}
+	}()
+
+	log.Println("working...")
+}
+
+// RunTwice only calls safe functions.
+func RunTwice() {
+	Run()
+	Run()
+}
+
+// Late does some work before it defers its recover, so a panic in that work isn't recovered.
+func Late() {
+	log.Println("starting...")
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("recover: %v\n", r)
+		}
+	}()
+
+	log.Println("working...")
+}